@@ -0,0 +1,361 @@
+package helpers
+
+import (
+	"math"
+	"math/cmplx"
+	"reflect"
+)
+
+// Compares an expected return value to the value actually returned.
+// Returns true if the actual value should be accepted.
+type Comparator func(expected reflect.Value, actual reflect.Value) bool
+
+// Option that changes how Equal compares values
+type CompareOption func(*compareOptions)
+
+// Options used by Equal
+type compareOptions struct {
+	useTolerance     bool
+	tolerance        float64
+	unorderedSlices  bool
+	ignoreUnexported bool
+}
+
+// Floating point (and complex) numbers only need to be within epsilon of each other.
+// NaN is treated as equal to NaN.
+func FloatTolerance(epsilon float64) CompareOption {
+	return func(o *compareOptions) {
+		o.useTolerance = true
+		o.tolerance = epsilon
+	}
+}
+
+// Slices (and arrays) are treated as collections where element order doesn't matter
+func UnorderedSlices() CompareOption {
+	return func(o *compareOptions) {
+		o.unorderedSlices = true
+	}
+}
+
+// Unexported struct fields are skipped when comparing structs
+func IgnoreUnexported() CompareOption {
+	return func(o *compareOptions) {
+		o.ignoreUnexported = true
+	}
+}
+
+// Returns a comparator that performs a deep comparison (like reflect.DeepEqual)
+// using the specified options (e.g., Equal(FloatTolerance(0.001), UnorderedSlices()))
+func Equal(options ...CompareOption) Comparator {
+
+	var o compareOptions
+	for _, option := range options {
+		option(&o)
+	}
+
+	return func(expected reflect.Value, actual reflect.Value) bool {
+		return deepEqual(expected, actual, o, map[comparison]bool{})
+	}
+}
+
+// Returns a comparator that accepts floating point values within epsilon of the expected value
+func ApproxFloat(epsilon float64) Comparator {
+	return Equal(FloatTolerance(epsilon))
+}
+
+// Returns a comparator that accepts slices holding the expected elements in any order
+func Unordered() Comparator {
+	return Equal(UnorderedSlices())
+}
+
+// Returns a comparator that uses the provided function to compare the values
+func CompareFunc(compare func(expected interface{}, actual interface{}) bool) Comparator {
+	return func(expected reflect.Value, actual reflect.Value) bool {
+		return compare(expected.Interface(), actual.Interface())
+	}
+}
+
+// Pair of references (pointers, maps or slices) being compared by deepEqual
+type comparison struct {
+	x   uintptr
+	y   uintptr
+	typ reflect.Type
+}
+
+// Returns true if the values are deeply equal using the specified options.
+// inProgress holds the references currently being compared further up the call
+// stack. Reaching one of them again means the data is cyclic, and (like
+// reflect.DeepEqual) that pair is treated as equal so the cycle isn't followed forever.
+func deepEqual(x reflect.Value, y reflect.Value, o compareOptions, inProgress map[comparison]bool) bool {
+
+	if !x.IsValid() || !y.IsValid() {
+		return x.IsValid() == y.IsValid()
+	}
+
+	if x.Type() != y.Type() {
+		return false
+	}
+
+	switch x.Kind() {
+
+	case reflect.Pointer, reflect.Map, reflect.Slice:
+
+		if !x.IsNil() && !y.IsNil() {
+
+			c := comparison{x: x.Pointer(), y: y.Pointer(), typ: x.Type()}
+
+			if inProgress[c] {
+				return true
+			}
+
+			// Removed once the comparison is done since unordered comparisons
+			// try pairs that end up not matching
+			inProgress[c] = true
+			defer delete(inProgress, c)
+		}
+	}
+
+	switch x.Kind() {
+
+	case reflect.Float32, reflect.Float64:
+
+		a, b := x.Float(), y.Float()
+
+		if o.useTolerance {
+			return (math.IsNaN(a) && math.IsNaN(b)) || a == b || math.Abs(a-b) <= o.tolerance
+		}
+
+		return a == b
+
+	case reflect.Complex64, reflect.Complex128:
+
+		a, b := x.Complex(), y.Complex()
+
+		if o.useTolerance {
+			return (cmplx.IsNaN(a) && cmplx.IsNaN(b)) || a == b || cmplx.Abs(a-b) <= o.tolerance
+		}
+
+		return a == b
+
+	case reflect.Bool:
+		return x.Bool() == y.Bool()
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return x.Int() == y.Int()
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return x.Uint() == y.Uint()
+
+	case reflect.String:
+		return x.String() == y.String()
+
+	case reflect.Pointer:
+
+		if x.Pointer() == y.Pointer() {
+			return true
+		}
+
+		if x.IsNil() || y.IsNil() {
+			return false
+		}
+
+		return deepEqual(x.Elem(), y.Elem(), o, inProgress)
+
+	case reflect.Interface:
+
+		if x.IsNil() || y.IsNil() {
+			return x.IsNil() == y.IsNil()
+		}
+
+		return deepEqual(x.Elem(), y.Elem(), o, inProgress)
+
+	case reflect.Struct:
+
+		for i := 0; i < x.NumField(); i++ {
+
+			if o.ignoreUnexported && !x.Type().Field(i).IsExported() {
+				continue
+			}
+
+			if !deepEqual(x.Field(i), y.Field(i), o, inProgress) {
+				return false
+			}
+		}
+
+		return true
+
+	case reflect.Slice, reflect.Array:
+
+		if x.Kind() == reflect.Slice && x.IsNil() != y.IsNil() {
+			return false
+		}
+
+		if x.Len() != y.Len() {
+			return false
+		}
+
+		if o.unorderedSlices {
+			return sameElements(x, y, o, inProgress)
+		}
+
+		for i := 0; i < x.Len(); i++ {
+			if !deepEqual(x.Index(i), y.Index(i), o, inProgress) {
+				return false
+			}
+		}
+
+		return true
+
+	case reflect.Map:
+
+		if x.IsNil() != y.IsNil() || x.Len() != y.Len() {
+			return false
+		}
+
+		for _, key := range x.MapKeys() {
+
+			value := y.MapIndex(key)
+
+			if !value.IsValid() || !deepEqual(x.MapIndex(key), value, o, inProgress) {
+				return false
+			}
+		}
+
+		return true
+
+	case reflect.Func:
+		// Functions are only equal if both are nil (same as reflect.DeepEqual)
+		return x.IsNil() && y.IsNil()
+
+	default:
+		// Channels and unsafe pointers
+		return x.Pointer() == y.Pointer()
+	}
+}
+
+// Returns true if every element of x can be paired with a different, equal element of y
+// (both hold the same number of elements)
+func sameElements(x reflect.Value, y reflect.Value, o compareOptions, inProgress map[comparison]bool) bool {
+
+	if countable(x.Type().Elem(), o) && x.CanInterface() && y.CanInterface() {
+		return sameCounts(x, y)
+	}
+
+	// equal[i] = indexes of the elements of y equal to element i of x
+	equal := make([][]int, x.Len())
+
+	for i := range equal {
+		for j := 0; j < y.Len(); j++ {
+			if deepEqual(x.Index(i), y.Index(j), o, inProgress) {
+				equal[i] = append(equal[i], j)
+			}
+		}
+
+		if len(equal[i]) == 0 {
+			return false
+		}
+	}
+
+	return pairAll(equal, y.Len())
+}
+
+// Returns true if every item can be paired with a different target
+// (bipartite matching). options[i] = indexes of the targets item i can be paired with.
+// Each item is paired in turn, moving earlier pairs along augmenting paths when needed,
+// so this takes polynomial time instead of trying every combination.
+func pairAll(options [][]int, targets int) bool {
+
+	pairedWith := make([]int, targets) // item paired with each target (-1 = none)
+	for j := range pairedWith {
+		pairedWith[j] = -1
+	}
+
+	for i := range options {
+		if !augment(i, options, pairedWith, make([]bool, targets)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Tries to pair item i with a target, re-pairing
+// earlier items if that frees one up (augmenting path)
+func augment(i int, options [][]int, pairedWith []int, seen []bool) bool {
+
+	for _, j := range options[i] {
+
+		if seen[j] {
+			continue
+		}
+
+		seen[j] = true
+
+		if pairedWith[j] < 0 || augment(pairedWith[j], options, pairedWith, seen) {
+			pairedWith[j] = i
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns true if values of the type are deeply equal exactly when they're == (so they
+// can be counted using a map). Floating point numbers only qualify without a tolerance.
+func countable(t reflect.Type, o compareOptions) bool {
+
+	switch t.Kind() {
+
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+
+	case reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return !o.useTolerance
+
+	case reflect.Array:
+		// Nested arrays are compared unordered too
+		return !o.unorderedSlices && countable(t.Elem(), o)
+
+	case reflect.Struct:
+
+		for i := 0; i < t.NumField(); i++ {
+
+			if o.ignoreUnexported && !t.Field(i).IsExported() {
+				return false
+			}
+
+			if !countable(t.Field(i).Type, o) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// Returns true if x and y hold the same number of each element
+func sameCounts(x reflect.Value, y reflect.Value) bool {
+
+	counts := map[interface{}]int{}
+
+	for i := 0; i < x.Len(); i++ {
+		counts[x.Index(i).Interface()]++
+	}
+
+	for i := 0; i < y.Len(); i++ {
+
+		element := y.Index(i).Interface()
+
+		if counts[element] == 0 {
+			return false
+		}
+
+		counts[element]--
+	}
+
+	return true
+}
@@ -0,0 +1,152 @@
+package helpers
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+type comparePoint struct {
+	X, Y  float64
+	label string
+}
+
+type compareNode struct {
+	Value int
+	Next  *compareNode
+}
+
+func TestEqual(t *testing.T) {
+
+	cycle := &compareNode{Value: 1}
+	cycle.Next = cycle
+
+	otherCycle := &compareNode{Value: 1}
+	otherCycle.Next = otherCycle
+
+	differentCycle := &compareNode{Value: 1, Next: &compareNode{Value: 2}}
+	differentCycle.Next.Next = differentCycle
+
+	// Variables so the sum is computed at runtime (constant 0.1 + 0.2 is exactly 0.3)
+	tenth, fifth := 0.1, 0.2
+
+	tests := []struct {
+		name     string
+		compare  Comparator
+		expected interface{}
+		actual   interface{}
+		equal    bool
+	}{
+		{name: "exact float", compare: Equal(), expected: 0.3, actual: tenth + fifth, equal: false},
+		{name: "float within tolerance", compare: Equal(FloatTolerance(1e-9)), expected: 0.3, actual: tenth + fifth, equal: true},
+		{name: "float outside tolerance", compare: ApproxFloat(0.01), expected: 1.0, actual: 1.1, equal: false},
+		{name: "NaN with tolerance", compare: ApproxFloat(0.01), expected: math.NaN(), actual: math.NaN(), equal: true},
+		{name: "NaN without tolerance", compare: Equal(), expected: math.NaN(), actual: math.NaN(), equal: false},
+		{name: "infinity", compare: ApproxFloat(0.01), expected: math.Inf(1), actual: math.Inf(1), equal: true},
+		{name: "float32", compare: ApproxFloat(0.001), expected: float32(1.0), actual: float32(1.0005), equal: true},
+		{name: "complex", compare: ApproxFloat(0.01), expected: complex(1, 1), actual: complex(1.001, 0.999), equal: true},
+		{name: "different types", compare: Equal(), expected: 1, actual: int64(1), equal: false},
+		{name: "nested floats", compare: ApproxFloat(0.01), expected: map[string][]float64{"a": {1, 2}}, actual: map[string][]float64{"a": {1.001, 1.999}}, equal: true},
+		{name: "missing map key", compare: Equal(), expected: map[string]int{"a": 1}, actual: map[string]int{"b": 1}, equal: false},
+		{name: "ordered slices", compare: Equal(), expected: []int{1, 2, 3}, actual: []int{3, 2, 1}, equal: false},
+		{name: "unordered slices", compare: Unordered(), expected: []int{1, 2, 3}, actual: []int{3, 2, 1}, equal: true},
+		{name: "unordered duplicates", compare: Unordered(), expected: []int{1, 1, 2}, actual: []int{1, 2, 2}, equal: false},
+		{name: "unordered arrays", compare: Unordered(), expected: [3]string{"a", "b", "c"}, actual: [3]string{"c", "a", "b"}, equal: true},
+		{name: "unordered with tolerance", compare: Equal(UnorderedSlices(), FloatTolerance(0.1)), expected: []float64{1, 2}, actual: []float64{2.05, 0.95}, equal: true},
+		{name: "unordered nested", compare: Unordered(), expected: [][]int{{1, 2}, {3}}, actual: [][]int{{3}, {2, 1}}, equal: true},
+		{name: "nil and empty slice", compare: Equal(), expected: []int(nil), actual: []int{}, equal: false},
+		{name: "unexported field", compare: Equal(), expected: comparePoint{1, 2, "a"}, actual: comparePoint{1, 2, "b"}, equal: false},
+		{name: "ignore unexported", compare: Equal(IgnoreUnexported()), expected: comparePoint{1, 2, "a"}, actual: comparePoint{1, 2, "b"}, equal: true},
+		{name: "ignore unexported checks exported", compare: Equal(IgnoreUnexported()), expected: comparePoint{1, 2, "a"}, actual: comparePoint{1, 3, "a"}, equal: false},
+		{name: "pointers", compare: Equal(), expected: &comparePoint{X: 1}, actual: &comparePoint{X: 1}, equal: true},
+		{name: "nil pointer", compare: Equal(), expected: (*comparePoint)(nil), actual: &comparePoint{}, equal: false},
+		{name: "errors", compare: Equal(), expected: errors.New("bad"), actual: errors.New("bad"), equal: true},
+		{name: "cycles", compare: Equal(), expected: cycle, actual: otherCycle, equal: true},
+		{name: "different cycles", compare: Equal(), expected: cycle, actual: differentCycle, equal: false},
+		{name: "long lists", compare: Equal(), expected: compareList(200, -1), actual: compareList(200, -1), equal: true},
+		{name: "long lists differing near the end", compare: Equal(), expected: compareList(200, -1), actual: compareList(200, 150), equal: false},
+		{name: "unordered structs", compare: Unordered(), expected: []comparePoint{{1, 2, "a"}, {3, 4, "b"}, {1, 2, "a"}}, actual: []comparePoint{{1, 2, "a"}, {1, 2, "a"}, {3, 4, "b"}}, equal: true},
+		{name: "unordered interfaces", compare: Unordered(), expected: []interface{}{1, "a", 1}, actual: []interface{}{"a", 1, 1}, equal: true},
+		{name: "unordered pointers", compare: Unordered(), expected: []*int{compareInt(1), compareInt(2)}, actual: []*int{compareInt(2), compareInt(1)}, equal: true},
+		{name: "unordered needs re-pairing", compare: Equal(UnorderedSlices(), FloatTolerance(1)), expected: []float64{1, 2}, actual: []float64{2, 0.5}, equal: true},
+		{name: "unordered NaN", compare: Unordered(), expected: []float64{math.NaN()}, actual: []float64{math.NaN()}, equal: false},
+		{name: "compare function", compare: CompareFunc(func(e interface{}, a interface{}) bool { return a.(int)%2 == e.(int)%2 }), expected: 1, actual: 7, equal: true},
+	}
+
+	for _, test := range tests {
+
+		if equal := test.compare(reflect.ValueOf(test.expected), reflect.ValueOf(test.actual)); equal != test.equal {
+			t.Errorf("%s: comparing %v to %v returned %v, expected %v", test.name, test.expected, test.actual, equal, test.equal)
+		}
+	}
+}
+
+// Builds a linked list of n nodes holding 0 to n-1 (except node changed, which holds -1)
+func compareList(n int, changed int) *compareNode {
+
+	var head *compareNode
+
+	for i := n - 1; i >= 0; i-- {
+
+		value := i
+		if i == changed {
+			value = -1
+		}
+
+		head = &compareNode{Value: value, Next: head}
+	}
+
+	return head
+}
+
+// Returns a pointer to the value
+func compareInt(n int) *int {
+	return &n
+}
+
+func TestUnorderedLargeSlices(t *testing.T) {
+
+	// Many equal elements and one that doesn't match
+	// (trying every pairing of these would never finish)
+	tests := []struct {
+		name     string
+		compare  Comparator
+		expected interface{}
+		actual   interface{}
+	}{
+		{name: "ints", compare: Unordered(), expected: make([]int, 1000), actual: append(make([]int, 999), 1)},
+		{name: "floats with tolerance", compare: Equal(UnorderedSlices(), FloatTolerance(0.01)), expected: make([]float64, 300), actual: append(make([]float64, 299), 1)},
+		{name: "slices", compare: Unordered(), expected: make([][]int, 300), actual: append(make([][]int, 299), []int{1})},
+	}
+
+	for _, test := range tests {
+
+		if test.compare(reflect.ValueOf(test.expected), reflect.ValueOf(test.actual)) {
+			t.Errorf("%s: expected the slices not to match", test.name)
+		}
+
+		if !test.compare(reflect.ValueOf(test.expected), reflect.ValueOf(test.expected)) {
+			t.Errorf("%s: expected a slice to match itself", test.name)
+		}
+	}
+}
+
+func TestEqualMatchesDeepEqual(t *testing.T) {
+
+	values := []interface{}{
+		nil, 1, "a", []int{1}, []int(nil), map[string]int{"a": 1}, map[string]int(nil),
+		comparePoint{1, 2, "a"}, &comparePoint{1, 2, "a"}, []interface{}{1, "a", nil}, func() {},
+	}
+
+	compare := Equal()
+
+	for _, x := range values {
+		for _, y := range values {
+
+			if equal, expected := compare(reflect.ValueOf(x), reflect.ValueOf(y)), reflect.DeepEqual(x, y); equal != expected {
+				t.Errorf("comparing %#v to %#v returned %v, reflect.DeepEqual returned %v", x, y, equal, expected)
+			}
+		}
+	}
+}
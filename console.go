@@ -0,0 +1,115 @@
+package helpers
+
+import (
+	"os"
+	"sync"
+)
+
+// Redirects stdin/stdout like an outcap container, except the output can be
+// read while the function is still running (an outcap container's OutData is
+// only safe to read after Stop). Used to run interaction scripts.
+type console struct {
+	mu     sync.Mutex
+	output []byte
+	closed bool // true once all of the output has been read
+
+	notify chan struct{} // signaled whenever output arrives (or the output is closed)
+	done   chan struct{} // closed once the reader goroutine finishes
+
+	oldIn, oldOut *os.File
+	inReader      *os.File
+	inWriter      *os.File
+	outWriter     *os.File
+	stopOnce      sync.Once
+}
+
+// Starts redirecting stdin/stdout to a new console
+func startConsole() (*console, error) {
+
+	inReader, inWriter, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+
+	outReader, outWriter, err := os.Pipe()
+	if err != nil {
+		inReader.Close()
+		inWriter.Close()
+		return nil, err
+	}
+
+	c := &console{
+		notify:    make(chan struct{}, 1),
+		done:      make(chan struct{}),
+		oldIn:     os.Stdin,
+		oldOut:    os.Stdout,
+		inReader:  inReader,
+		inWriter:  inWriter,
+		outWriter: outWriter,
+	}
+
+	os.Stdin, os.Stdout = inReader, outWriter
+
+	go func() {
+
+		defer close(c.done)
+		defer outReader.Close()
+
+		buffer := make([]byte, 4096)
+
+		for {
+			n, err := outReader.Read(buffer)
+
+			c.mu.Lock()
+			c.output = append(c.output, buffer[:n]...)
+			c.closed = err != nil
+			c.mu.Unlock()
+
+			c.signal()
+
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	return c, nil
+}
+
+// Wakes up anything waiting for output (without blocking)
+func (c *console) signal() {
+	select {
+	case c.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Returns a copy of everything written to stdout so far
+// and whether the output has been closed
+func (c *console) snapshot() (string, bool) {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return string(c.output), c.closed
+}
+
+// Writes a line of input to stdin
+func (c *console) send(line string) {
+	c.inWriter.WriteString(line + "\n")
+}
+
+// Stops redirecting stdin/stdout and waits for the remaining output to be read
+func (c *console) stop() {
+
+	c.stopOnce.Do(func() {
+
+		os.Stdin, os.Stdout = c.oldIn, c.oldOut
+
+		c.outWriter.Close()
+		c.inWriter.Close()
+		c.inReader.Close()
+
+		<-c.done
+	})
+}
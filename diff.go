@@ -0,0 +1,203 @@
+package helpers
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Number of unchanged lines shown around each change in a diff
+const diffContextLines = 3
+
+// Single line of a diff.
+// kind = ' ' (line in both), '-' (expected line missing) or '+' (unexpected actual line)
+type diffEdit struct {
+	kind     byte
+	expected int // index into the expected lines (unused for '+')
+	actual   int // index into the actual lines (unused for '-')
+}
+
+// Computes the shortest list of edits that turns the expected lines into
+// the actual lines (longest common subsequence). equal(i, j) reports whether
+// expected line i matches actual line j.
+func diffEdits(expectedCount int, actualCount int, equal func(i int, j int) bool) []diffEdit {
+
+	// lcs[i][j] = length of the longest common subsequence of expected[i:] and actual[j:]
+	lcs := make([][]int, expectedCount+1)
+	for i := range lcs {
+		lcs[i] = make([]int, actualCount+1)
+	}
+
+	for i := expectedCount - 1; i >= 0; i-- {
+		for j := actualCount - 1; j >= 0; j-- {
+
+			if equal(i, j) {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var edits []diffEdit
+	i, j := 0, 0
+
+	for i < expectedCount && j < actualCount {
+
+		if equal(i, j) {
+			edits = append(edits, diffEdit{kind: ' ', expected: i, actual: j})
+			i++
+			j++
+		} else if lcs[i+1][j] >= lcs[i][j+1] {
+			edits = append(edits, diffEdit{kind: '-', expected: i})
+			i++
+		} else {
+			edits = append(edits, diffEdit{kind: '+', actual: j})
+			j++
+		}
+	}
+
+	for ; i < expectedCount; i++ {
+		edits = append(edits, diffEdit{kind: '-', expected: i})
+	}
+
+	for ; j < actualCount; j++ {
+		edits = append(edits, diffEdit{kind: '+', actual: j})
+	}
+
+	return edits
+}
+
+// Replaces spaces and tabs with visible characters so whitespace differences stand out
+func visibleWhitespace(text string) string {
+	return strings.NewReplacer(" ", "·", "\t", "→", "\r", "␍").Replace(text)
+}
+
+// Builds a line-aligned unified diff of the expected and actual lines.
+// equal(i, j) reports whether expected line i matches actual line j.
+// If hideExpected is true, the expected text is left out and only the
+// actual lines are shown (unexpected lines are marked with '+').
+func unifiedDiff(expected []string, actual []string, equal func(i int, j int) bool, hideExpected bool) string {
+
+	edits := diffEdits(len(expected), len(actual), equal)
+
+	var sb strings.Builder
+
+	if hideExpected {
+		sb.WriteString("Actual output (lines marked with + don't match the expected output):\n")
+		sb.WriteString("+++ actual\n")
+	} else {
+		sb.WriteString("Differences between expected and actual output:\n")
+		sb.WriteString("--- expected\n")
+		sb.WriteString("+++ actual\n")
+	}
+
+	for start := 0; start < len(edits); {
+
+		// Find the next change
+		for start < len(edits) && edits[start].kind == ' ' {
+			start++
+		}
+
+		if start == len(edits) {
+			break
+		}
+
+		// Extend the hunk until there are enough unchanged lines in a row to end it
+		end := start
+		for unchanged := 0; end < len(edits) && unchanged <= 2*diffContextLines; end++ {
+			if edits[end].kind == ' ' {
+				unchanged++
+			} else {
+				unchanged = 0
+			}
+		}
+
+		// Trim trailing unchanged lines down to the context size
+		for end > start && edits[end-1].kind == ' ' {
+			end--
+		}
+
+		hunkStart := start - diffContextLines
+		if hunkStart < 0 {
+			hunkStart = 0
+		}
+
+		hunkEnd := end + diffContextLines
+		if hunkEnd > len(edits) {
+			hunkEnd = len(edits)
+		}
+
+		writeDiffHunk(&sb, expected, actual, edits[hunkStart:hunkEnd], hideExpected)
+
+		start = hunkEnd
+	}
+
+	sb.WriteString("(· = space, → = tab)")
+
+	return sb.String()
+}
+
+// Writes a single hunk (header followed by its lines) of a unified diff
+func writeDiffHunk(sb *strings.Builder, expected []string, actual []string, hunk []diffEdit, hideExpected bool) {
+
+	// A side can only be empty in a hunk if it's empty in the whole diff
+	// (hunks always include the surrounding unchanged lines)
+	expectedStart, actualStart := -1, -1
+	expectedCount, actualCount := 0, 0
+
+	for _, edit := range hunk {
+
+		if edit.kind != '+' {
+			if expectedStart < 0 {
+				expectedStart = edit.expected
+			}
+			expectedCount++
+		}
+
+		if edit.kind != '-' {
+			if actualStart < 0 {
+				actualStart = edit.actual
+			}
+			actualCount++
+		}
+	}
+
+	if hideExpected {
+		sb.WriteString("@@ +" + diffRange(actualStart, actualCount) + " @@\n")
+	} else {
+		sb.WriteString("@@ -" + diffRange(expectedStart, expectedCount) + " +" + diffRange(actualStart, actualCount) + " @@\n")
+	}
+
+	for _, edit := range hunk {
+
+		switch edit.kind {
+
+		case ' ':
+			sb.WriteString(" " + visibleWhitespace(actual[edit.actual]) + "\n")
+
+		case '-':
+			if !hideExpected {
+				sb.WriteString("-" + visibleWhitespace(expected[edit.expected]) + "\n")
+			}
+
+		case '+':
+			sb.WriteString("+" + visibleWhitespace(actual[edit.actual]) + "\n")
+		}
+	}
+}
+
+// Formats a unified diff line range (1 based)
+func diffRange(start int, count int) string {
+
+	if count == 0 {
+		return "0,0"
+	}
+
+	if count == 1 {
+		return strconv.Itoa(start + 1)
+	}
+
+	return strconv.Itoa(start+1) + "," + strconv.Itoa(count)
+}
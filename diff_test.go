@@ -0,0 +1,102 @@
+package helpers
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+
+	numbered := []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12"}
+	changed := []string{"one", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "twelve"}
+
+	tests := []struct {
+		name       string
+		expected   []string
+		actual     []string
+		ignoreCase bool
+		hide       bool
+		hunks      string
+	}{
+		{
+			name:     "no differences",
+			expected: []string{"a", "b"},
+			actual:   []string{"a", "b"},
+		},
+		{
+			name:     "changed line",
+			expected: []string{"a", "b", "c"},
+			actual:   []string{"a", "x", "c"},
+			hunks:    "@@ -1,3 +1,3 @@\n a\n-b\n+x\n c\n",
+		},
+		{
+			name:     "whitespace",
+			expected: []string{"a b"},
+			actual:   []string{"a  b\t"},
+			hunks:    "@@ -1 +1 @@\n-a·b\n+a··b→\n",
+		},
+		{
+			name:     "missing line",
+			expected: []string{"a", "b"},
+			actual:   []string{"a"},
+			hunks:    "@@ -1,2 +1 @@\n a\n-b\n",
+		},
+		{
+			name:     "extra line",
+			expected: []string{"b"},
+			actual:   []string{"a", "b"},
+			hunks:    "@@ -1 +1,2 @@\n+a\n b\n",
+		},
+		{
+			name:     "no output",
+			expected: []string{"a"},
+			hunks:    "@@ -1 +0,0 @@\n-a\n",
+		},
+		{
+			name:     "separate hunks",
+			expected: numbered,
+			actual:   changed,
+			hunks:    "@@ -1,4 +1,4 @@\n-1\n+one\n 2\n 3\n 4\n@@ -9,4 +9,4 @@\n 9\n 10\n 11\n-12\n+twelve\n",
+		},
+		{
+			name:     "merged hunk",
+			expected: numbered[:8],
+			actual:   append(append([]string{"one"}, numbered[1:7]...), "eight"),
+			hunks:    "@@ -1,8 +1,8 @@\n-1\n+one\n 2\n 3\n 4\n 5\n 6\n 7\n-8\n+eight\n",
+		},
+		{
+			name:       "custom comparison",
+			expected:   []string{"Hello", "World"},
+			actual:     []string{"hello", "world"},
+			ignoreCase: true,
+		},
+		{
+			name:     "hidden expected output",
+			expected: []string{"a", "b", "c"},
+			actual:   []string{"a", "x", "c"},
+			hide:     true,
+			hunks:    "@@ +1,3 @@\n a\n+x\n c\n",
+		},
+	}
+
+	for _, test := range tests {
+
+		equal := func(i int, j int) bool {
+			if test.ignoreCase {
+				return strings.EqualFold(test.expected[i], test.actual[j])
+			}
+			return test.expected[i] == test.actual[j]
+		}
+
+		header := "Differences between expected and actual output:\n--- expected\n+++ actual\n"
+		if test.hide {
+			header = "Actual output (lines marked with + don't match the expected output):\n+++ actual\n"
+		}
+
+		expected := header + test.hunks + "(· = space, → = tab)"
+
+		if actual := unifiedDiff(test.expected, test.actual, equal, test.hide); actual != expected {
+			t.Errorf("%s: got\n%s\nexpected\n%s", test.name, actual, expected)
+		}
+	}
+}
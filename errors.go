@@ -0,0 +1,145 @@
+package helpers
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+)
+
+// Checks an error returned by a function (or the value a function panicked with).
+// Used instead of reflect.DeepEqual, which doesn't match errors created by
+// different calls (e.g., fmt.Errorf("bad value %d", n)).
+type ErrorMatcher interface {
+	Match(err error) bool
+	Expected() string // description of the expected error (used in failure messages)
+}
+
+// Error matcher built from a description and a function
+type errorMatcher struct {
+	description string
+	match       func(err error) bool
+}
+
+func (m errorMatcher) Match(err error) bool {
+	return m.match(err)
+}
+
+func (m errorMatcher) Expected() string {
+	return m.description
+}
+
+// Matches a nil error
+func ErrNil() ErrorMatcher {
+	return errorMatcher{
+		description: "nil",
+		match: func(err error) bool {
+			return err == nil
+		},
+	}
+}
+
+// Matches any non-nil error
+func ErrNotNil() ErrorMatcher {
+	return errorMatcher{
+		description: "a non-nil error",
+		match: func(err error) bool {
+			return err != nil
+		},
+	}
+}
+
+// Matches an error that is (or wraps) the target (see errors.Is)
+func ErrorIs(target error) ErrorMatcher {
+	return errorMatcher{
+		description: "an error matching " + describeError(target) + " (errors.Is)",
+		match: func(err error) bool {
+			return errors.Is(err, target)
+		},
+	}
+}
+
+// Matches an error that is (or wraps) an error of the target's type (see errors.As).
+// target must be a non-nil pointer to an error type or interface (e.g., new(*fs.PathError)).
+func ErrorAs(target interface{}) ErrorMatcher {
+
+	targetType := reflect.TypeOf(target)
+	if targetType == nil || targetType.Kind() != reflect.Pointer || reflect.ValueOf(target).IsNil() {
+		panic("ErrorAs requires a non-nil pointer (e.g., new(*fs.PathError))")
+	}
+
+	errorType := reflect.TypeOf((*error)(nil)).Elem()
+	if targetType.Elem().Kind() != reflect.Interface && !targetType.Elem().Implements(errorType) {
+		panic("ErrorAs requires a pointer to an error type or interface, found " + targetType.String())
+	}
+
+	return errorMatcher{
+		description: "an error of type " + targetType.Elem().String() + " (errors.As)",
+		match: func(err error) bool {
+			// A new target for each match so the caller's target is never modified
+			return err != nil && errors.As(err, reflect.New(targetType.Elem()).Interface())
+		},
+	}
+}
+
+// Matches a non-nil error whose message (err.Error()) matches the output matcher
+// (e.g., ErrorMessage(Contains("not found")) or ErrorMessage(Regex(`invalid value \d+`)))
+func ErrorMessage(matcher OutputMatcher) ErrorMatcher {
+
+	if matcher.Lines() != 1 {
+		panic("ErrorMessage requires a matcher for a single line")
+	}
+
+	return errorMatcher{
+		description: "an error with message " + matcher.Expected()[0],
+		match: func(err error) bool {
+			return err != nil && matcher.Match([]string{err.Error()})
+		},
+	}
+}
+
+// Returns a reflect.Value holding err with the type error (even when err is nil).
+// Use it for error return values so the anatomy test sees the right type
+// (reflect.ValueOf(nil) isn't a valid value and reflect.ValueOf(err) has err's concrete type).
+func ErrorValue(err error) reflect.Value {
+	return reflect.ValueOf(&err).Elem()
+}
+
+// Describes an error for failure messages (e.g., "file not found" (*errors.errorString))
+func describeError(err error) string {
+
+	if err == nil {
+		return "nil"
+	}
+
+	return strconv.Quote(err.Error()) + " (" + reflect.TypeOf(err).String() + ")"
+}
+
+// Panic value that isn't an error (e.g., panic("bad input"))
+type panicValueError struct {
+	value interface{}
+}
+
+func (e panicValueError) Error() string {
+	return panicValueText(e.value)
+}
+
+// Converts the value a function panicked with to an error so it can be checked using an ErrorMatcher
+func panicAsError(value interface{}) error {
+
+	if err, ok := value.(error); ok {
+		return err
+	}
+
+	return panicValueError{value}
+}
+
+// Converts a returned value to an error (nil if it isn't one)
+func returnedError(v reflect.Value) error {
+
+	if !v.IsValid() || ((v.Kind() == reflect.Interface || v.Kind() == reflect.Pointer) && v.IsNil()) {
+		return nil
+	}
+
+	err, _ := v.Interface().(error)
+	return err
+}
@@ -0,0 +1,447 @@
+package helpers
+
+import (
+	"math/rand"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/savantes1/outcap"
+)
+
+// Standard message used when a runtime error (panic) occurs in the code being tested
+const runtimeErrorMessage = "A runtime error occurred while attempting to run this unit test.\nThere are a variety of situations that can cause runtime errors (e.g., accessing array index out of range, dereferencing a nil pointer, etc.).\nReview your code and/or contact the instructor for assistance."
+
+// Output test shared by the function and method runners.
+// Both FuncOutputTest and MethodOutputTest are converted to this
+// once the function (or method) to call has been found.
+type outputCase struct {
+	description    string // start of every error message (e.g., "Function 'Sum'")
+	function       reflect.Value
+	args           []reflect.Value
+	variadic       bool
+	stdinStrings   []string
+	ignoreStdout   bool
+	stdoutStrings  []string
+	stdoutMatchers []OutputMatcher // used instead of stdoutStrings when set
+	ignoreStderr   bool
+	stderrStrings  []string
+	stderrMatchers []OutputMatcher // used instead of stderrStrings when set
+	hideExpected   bool
+	ignoreReturns  bool
+	returns        []reflect.Value
+	returnDetail   Detail
+	comparators    []Comparator   // comparator for each return position (nil = reflect.DeepEqual)
+	errorMatchers  []ErrorMatcher // used instead of the comparator for each return position (nil = not used)
+	expectPanic    bool
+	panicMatcher   ErrorMatcher // nil = any panic
+	timeout        time.Duration
+	isolate        bool
+	expectExit     bool // true if the function should end the program with exitCode
+	exitCode       int
+	keepsState     bool // true if later cases depend on the state left behind by this one (e.g., methods)
+
+	// Used instead of comparing the returns when set. Runs in the process that called
+	// the function (the isolated child process), so it can look at the state the call left behind.
+	checkCall func(result execution) string
+}
+
+// Results of calling a function under test
+type execution struct {
+	returnVals  []reflect.Value
+	stdout      []string
+	stderr      []string // only captured if stderr is checked
+	completed   bool     // true if the function returned normally
+	panicked    bool
+	panicValue  interface{}
+	panicReport string // runtime error message describing the panic (see runtimeErrorReport)
+	timedOut    bool
+	exited      bool // true if the function ended the program (os.Exit or log.Fatal)
+	exitCode    int
+}
+
+// Returns the message describing the runtime error the function caused
+func (result execution) runtimeErrorMessage() string {
+
+	if result.panicReport == "" {
+		return runtimeErrorMessage
+	}
+
+	return result.panicReport
+}
+
+// Message the testing package panics with when os.Exit(0) is called during a test
+const exitZeroPanic = "unexpected call to os.Exit(0) during test"
+
+// Calls the function in this process while feeding it the stdin strings
+// and capturing everything it writes to stdout.
+// If the function doesn't finish before the timeout it is abandoned. The abandoned
+// goroutine keeps running: it can still change shared state (e.g., the object a method
+// was called on) and write to whatever stdout/stderr are once they've been restored.
+// Its results are never reported. Isolate the test if that could affect later tests.
+func (oc outputCase) execute(randomSeed int64) execution {
+
+	//TODO: handle errors, maybe?
+	c, _ := outcap.NewContainer('\n')
+
+	var stderr *stderrCapture
+	if oc.checksStderr() {
+		stderr = captureStderr()
+	}
+
+	result := oc.call(randomSeed, c.WriteToStdin)
+
+	c.Stop() // stop redirecting stdin/stdout
+
+	result.stdout = c.OutData
+
+	if stderr != nil {
+		result.stderr = stderr.stop()
+	}
+
+	return result
+}
+
+// Calls the function in a separate goroutine after writing each stdin string using writeStdin.
+// Waits for the function to finish or time out (the output isn't collected).
+func (oc outputCase) call(randomSeed int64, writeStdin func(s string)) execution {
+
+	// Buffered so an abandoned goroutine never blocks forever
+	done := make(chan execution, 1)
+
+	go func() {
+
+		var result execution
+
+		// Handle any runtime errors that may have occurred
+		defer func() {
+			if err := recover(); err != nil {
+
+				// os.Exit(0) panics instead of exiting when running under go test
+				if err == exitZeroPanic {
+					result.exited = true
+				} else {
+					result.panicked = true
+					result.panicValue = err
+					result.panicReport = runtimeErrorReport(err)
+				}
+			}
+
+			done <- result
+		}()
+
+		// In case this function uses random numbers, make sure to set
+		// the seed to specified seed value so the "random" numbers will
+		// be predictable (deterministic) and will match the expected output
+		rand.Seed(randomSeed)
+
+		// Call the function...
+		result.returnVals = callFunction(oc.function, oc.args, oc.variadic)
+		result.completed = true
+	}()
+
+	// Write each input string to function
+	for _, s := range oc.stdinStrings {
+		writeStdin(s)
+	}
+
+	// Wait for goroutine to finish or time out in case the function
+	// tries to process more stdin input than what is expected
+	timer := time.NewTimer(scaledTimeout(oc.timeout))
+	defer timer.Stop()
+
+	var result execution
+
+	select {
+	case result = <-done:
+	case <-timer.C:
+		result.timedOut = true
+	}
+
+	return result
+}
+
+// Compares the results of running the function to what was expected.
+// Returns an error message for each problem found (nil if everything matched).
+func (oc outputCase) check(result execution) []string {
+	return oc.checkOutcome(result, oc.checkReturns(result))
+}
+
+// Checks the results of running the function using the message from checkReturns
+// (isolated child processes check the returns themselves since they can't be sent to the parent).
+// Returns an error message for each problem found (nil if everything matched).
+func (oc outputCase) checkOutcome(result execution, returnMessage string) []string {
+
+	// If function timed out, it probably means that there was an unexpected fmt.Scanln()
+	if result.timedOut {
+		return []string{oc.timedOutMessage()}
+	}
+
+	if message := oc.checkExit(result); message != "" {
+		return []string{message}
+	}
+
+	// A runtime error could have caused an error, so check for that before proceeding
+	if result.panicked && !oc.expectPanic {
+		return []string{result.runtimeErrorMessage()}
+	}
+
+	if returnMessage != "" {
+		// Only one error is returned
+		return []string{returnMessage}
+	}
+
+	if !oc.ignoreStdout {
+
+		if message := oc.checkStdout(result.stdout); message != "" {
+			return []string{message}
+		}
+	}
+
+	if oc.checksStderr() {
+
+		if message := oc.checkStderr(result.stderr); message != "" {
+			return []string{message}
+		}
+	}
+
+	return nil
+}
+
+// Compares the return values to the expected values.
+// Returns an error message for the first one that doesn't match ("" if they all match
+// or the function didn't return normally).
+func (oc outputCase) checkReturns(result execution) string {
+
+	if oc.expectPanic {
+		return oc.checkPanic(result)
+	}
+
+	if oc.ignoreReturns || !result.completed {
+		return ""
+	}
+
+	if oc.checkCall != nil {
+		return oc.checkCall(result)
+	}
+
+	for j := 0; j < len(oc.returns); j++ {
+
+		if j < len(oc.errorMatchers) && oc.errorMatchers[j] != nil {
+
+			if err := returnedError(result.returnVals[j]); !oc.errorMatchers[j].Match(err) {
+				return oc.errorMismatchMessage(j, err)
+			}
+
+			continue
+		}
+
+		if !oc.returnMatches(j, result.returnVals[j]) {
+			return oc.returnMismatchMessage(j, result.returnVals[j])
+		}
+	}
+
+	return ""
+}
+
+// Checks that the function panicked with the expected value.
+// Returns an error message if it didn't ("" if it did or didn't finish).
+func (oc outputCase) checkPanic(result execution) string {
+
+	expected := "any value"
+	if oc.panicMatcher != nil {
+		expected = oc.panicMatcher.Expected()
+	}
+
+	if oc.hideExpected {
+		expected = "(hidden)"
+	}
+
+	switch {
+
+	case result.completed:
+		return oc.description + " returned instead of panicking.\nExpected panic: " + expected
+
+	case result.panicked && oc.panicMatcher != nil && !oc.panicMatcher.Match(panicAsError(result.panicValue)):
+		return oc.description + " panicked with an unexpected value.\nExpected panic: " + expected +
+			"\nActual panic:   " + panicValueText(result.panicValue)
+	}
+
+	return ""
+}
+
+// Builds the error message used when the error returned at position j doesn't match
+func (oc outputCase) errorMismatchMessage(j int, err error) string {
+
+	message := oc.description + " returned an unexpected error. Be sure to check that your function returns the right error (or nil) for the arguments passed to the function or data supplied by the user."
+
+	if oc.detail() != DetailValues {
+		return message
+	}
+
+	message += "\nArguments: " + formatArguments(oc.args, oc.variadic)
+
+	if len(oc.returns) > 1 {
+		message += "\nUnexpected return value at position " + strconv.Itoa(j) + " (of " + strconv.Itoa(len(oc.returns)) + " return values)"
+	}
+
+	return message + "\nExpected: " + oc.errorMatchers[j].Expected() + "\nActual:   " + describeError(err)
+}
+
+// Checks whether the function ended the program as expected.
+// Returns an error message if it didn't ("" if it did or wasn't supposed to).
+func (oc outputCase) checkExit(result execution) string {
+
+	exitCode := strconv.Itoa(oc.exitCode)
+
+	switch {
+
+	case result.exited && !oc.expectExit:
+		return oc.description + " ended the program (e.g., by calling os.Exit or log.Fatal) with exit status " + strconv.Itoa(result.exitCode) +
+			" before completing. Functions should return instead of ending the program."
+
+	case result.exited && result.exitCode != oc.exitCode:
+		return oc.description + " ended the program with exit status " + strconv.Itoa(result.exitCode) + ", expected exit status " + exitCode + "."
+
+	case !result.exited && oc.expectExit && result.completed:
+		return oc.description + " returned instead of ending the program with exit status " + exitCode + " (e.g., by calling os.Exit(" + exitCode + "))."
+	}
+
+	return ""
+}
+
+// Splits output into lines the same way an outcap container does
+func outputLines(output string) []string {
+
+	if output == "" {
+		return nil
+	}
+
+	return strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+}
+
+// Returns true if the actual value is acceptable for the return value at position j
+func (oc outputCase) returnMatches(j int, actual reflect.Value) bool {
+
+	if j < len(oc.comparators) && oc.comparators[j] != nil {
+		return oc.comparators[j](oc.returns[j], actual)
+	}
+
+	return reflect.DeepEqual(oc.returns[j].Interface(), actual.Interface())
+}
+
+// Returns the amount of detail shown when a return value doesn't match
+func (oc outputCase) detail() Detail {
+
+	if oc.returnDetail != DetailDefault {
+		return oc.returnDetail
+	}
+
+	if oc.hideExpected {
+		return DetailSummary
+	}
+
+	return DefaultReturnDetail
+}
+
+// Builds the error message used when the return value at position j doesn't match
+func (oc outputCase) returnMismatchMessage(j int, actual reflect.Value) string {
+
+	message := oc.description + " returned unexpected value. This means that the value (not type) that was returned after calling the function did not match what was expected, given the arguments passed to the function or data supplied by the user. Be sure to test your function using many different input values to make sure it works in all scenarios."
+
+	if oc.detail() != DetailValues {
+		return message
+	}
+
+	message += "\nArguments: " + formatArguments(oc.args, oc.variadic)
+
+	if len(oc.stdinStrings) > 0 {
+		message += "\nUser input: " + formatValue(reflect.ValueOf(oc.stdinStrings))
+	}
+
+	if len(oc.returns) > 1 {
+		message += "\nUnexpected return value at position " + strconv.Itoa(j) + " (of " + strconv.Itoa(len(oc.returns)) + " return values)"
+	}
+
+	message += "\nExpected: " + formatValue(oc.returns[j]) + "\nActual:   " + formatValue(actual)
+
+	// Only list the differences when the values are too complex to compare at a glance
+	switch oc.returns[j].Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array, reflect.Pointer, reflect.Interface:
+		if differences := valueDifferences(oc.returns[j], actual); len(differences) > 0 {
+			message += "\nDifferences:\n  " + strings.Join(differences, "\n  ")
+		}
+	}
+
+	return message
+}
+
+// Compares the lines written to stdout to the expected lines.
+// Returns an error message (including a diff of the output) if they don't match.
+func (oc outputCase) checkStdout(stdout []string) string {
+	return oc.checkLines(oc.stdoutMatchers, oc.stdoutStrings, stdout,
+		" displayed unexpected number of output lines to the terminal.",
+		" displayed unexpected output to the terminal.")
+}
+
+// Returns true if the error output (stderr) should be checked
+func (oc outputCase) checksStderr() bool {
+	return !oc.ignoreStderr && (CheckStderr || oc.stderrStrings != nil || oc.stderrMatchers != nil)
+}
+
+// Compares the lines written to stderr to the expected lines.
+// Returns an error message (including a diff of the output) if they don't match.
+func (oc outputCase) checkStderr(stderr []string) string {
+	return oc.checkLines(oc.stderrMatchers, oc.stderrStrings, stderr,
+		" displayed unexpected number of error output lines (stderr).",
+		" displayed unexpected error output (stderr).")
+}
+
+// Compares output lines to the expected lines (matchers are used instead of the strings when set).
+// countProblem/lineProblem describe a line count mismatch and a line mismatch.
+func (oc outputCase) checkLines(matchers []OutputMatcher, expected []string, actual []string, countProblem string, lineProblem string) string {
+
+	if matchers == nil {
+		matchers = exactMatchers(expected)
+	}
+
+	comparison := newOutputComparison(matchers, actual)
+
+	if len(comparison.expected) != len(actual) {
+
+		return oc.description + countProblem + " Expected " +
+			strconv.Itoa(len(comparison.expected)) +
+			" line(s), found " + strconv.Itoa(len(actual)) + " line(s)\n" +
+			unifiedDiff(comparison.expected, actual, comparison.lineEqual, oc.hideExpected)
+	}
+
+	if j := comparison.firstMismatch(); j >= 0 {
+
+		// Only the first unexpected line is reported (the diff shows the rest)
+		return oc.description + lineProblem + " Unexpected output line: " + strconv.Itoa(j+1) +
+			"\nCommon output problems to double check: misspellings, incorrect character case, extra spaces\n" +
+			unifiedDiff(comparison.expected, actual, comparison.lineEqual, oc.hideExpected)
+	}
+
+	return ""
+}
+
+// Error message used when the function doesn't complete in time
+func (oc outputCase) timedOutMessage() string {
+	return oc.description + " timed out after " + scaledTimeout(oc.timeout).String() +
+		" before completing. Most likely issue is that the program is calling fmt.Scanln too many times."
+}
+
+// Runs the output test (in this process or an isolated child process).
+// Returns an error message for each problem found.
+func (oc outputCase) run(randomSeed int64, t *testing.T) []string {
+
+	// Ending the program can only be contained in a separate process
+	if oc.isolate || oc.expectExit || IsolateTests {
+		return runIsolatedCase(oc, randomSeed, t)
+	}
+
+	return oc.check(oc.execute(randomSeed))
+}
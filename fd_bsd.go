@@ -0,0 +1,9 @@
+//go:build unix && !linux
+
+package helpers
+
+import "syscall"
+
+func dup2(oldFd int, newFd int) error {
+	return syscall.Dup2(oldFd, newFd)
+}
@@ -0,0 +1,10 @@
+//go:build linux
+
+package helpers
+
+import "syscall"
+
+// Some linux architectures (e.g., arm64) don't have dup2
+func dup2(oldFd int, newFd int) error {
+	return syscall.Dup3(oldFd, newFd, 0)
+}
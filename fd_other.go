@@ -0,0 +1,14 @@
+//go:build !unix
+
+package helpers
+
+import (
+	"errors"
+	"os"
+)
+
+// File descriptors can't be redirected on this platform, so only
+// output written using os.Stderr (or the log package) is captured
+func redirectFd(fd int, f *os.File) (func(), error) {
+	return nil, errors.New("redirecting file descriptors is not supported on this platform")
+}
@@ -0,0 +1,28 @@
+//go:build unix
+
+package helpers
+
+import (
+	"os"
+	"syscall"
+)
+
+// Points the file descriptor at the file (e.g., so println output can be captured).
+// Returns a function that points it back at what it was before.
+func redirectFd(fd int, f *os.File) (func(), error) {
+
+	saved, err := syscall.Dup(fd)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := dup2(int(f.Fd()), fd); err != nil {
+		syscall.Close(saved)
+		return nil, err
+	}
+
+	return func() {
+		dup2(saved, fd)
+		syscall.Close(saved)
+	}, nil
+}
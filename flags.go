@@ -0,0 +1,330 @@
+package helpers
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// flag.Usage before any function under test could replace it
+var defaultFlagUsage = flag.Usage
+
+// Runtime flag testing struct.
+// Obj = function that defines the flags and parses the command line (e.g., main or a parseFlags
+// function). Before it's called, flag.CommandLine is replaced with an empty flag set that returns
+// errors instead of exiting and os.Args is set to Program followed by Args.
+// Program = program name used in usage text ("" = "program")
+// ExpectError = true if parsing the arguments should fail (this includes -h and -help)
+// ErrorStrings/ErrorMatchers = expected error output (the flag package's error message
+// followed by the usage text). Only checked when one of them is set.
+// Flags = expected state of each flag after parsing
+// UsageStrings/UsageMatchers = expected output of the usage function (flag.Usage). Only checked when one of them is set.
+// Timeout = amount of time the function is given to complete (0 = DefaultTimeout)
+// Reading user input (stdin) gets the end of the input right away.
+// Isolate = true to run the function in a separate process (see IsolateTests)
+// ExpectExit = true if the function should end the program (e.g., log.Fatal after validating
+// the flags) with ExitCode as the exit status. Only the exit status and ErrorStrings/ErrorMatchers
+// are checked. These tests are always run in a separate process.
+// Important: a function (e.g., main) that unexpectedly ends the program with a non-zero exit status
+// ends the whole test binary unless the test runs in a separate process (see FuncOutputTest).
+// Points/Category = weight and category used for scoring (see Scorecard; 0 Points = DefaultPoints)
+// Ungraded = true if the check is reported but worth no points (Points is ignored)
+type FlagTest struct {
+	Name          string
+	Obj           interface{}
+	Program       string
+	Args          []string
+	ExpectError   bool
+	ErrorStrings  []string
+	ErrorMatchers []OutputMatcher
+	Flags         []FlagExpectation
+	UsageStrings  []string
+	UsageMatchers []OutputMatcher
+	HideExpected  bool
+	Timeout       time.Duration
+	Isolate       bool
+	ExpectExit    bool
+	ExitCode      int
+	Points        float64
+	Ungraded      bool
+	Category      string
+}
+
+// Expected state of a single command line flag.
+// Value = value the bound variable should hold after parsing (e.g., 5, "text", 2.5, true; nil = not checked).
+// The type must match the flag's type (e.g., an int for flag.IntVar).
+// Default = default value (nil = not checked)
+// Usage = usage text ("" = not checked)
+type FlagExpectation struct {
+	Name    string
+	Value   interface{}
+	Default interface{}
+	Usage   string
+}
+
+// Runs runtime flag tests
+func RunFlagTests(tests []FlagTest, t *testing.T) {
+	RunFlagTestsWithMode(tests, DefaultFailureMode, t)
+}
+
+// Runs runtime flag tests using the specified failure mode
+func RunFlagTestsWithMode(tests []FlagTest, mode FailureMode, t *testing.T) {
+
+	for _, test := range tests {
+
+		// If a test failure has already occurred, no need to report further problems.
+		// The tests are still run so they can be scored.
+		runCase(t, mode, test.Name, !t.Failed(), func(t *testing.T) ScoreEntry {
+			return newScoreEntry(test.Name, test.Category, "Flags", test.Points, test.Ungraded, checkFlags(test, t))
+		})
+	}
+}
+
+// Runs the flag test.
+// Returns an error message for each problem found.
+func checkFlags(test FlagTest, t *testing.T) []string {
+
+	// The function must take no arguments and return nothing
+	if messages := checkFunctionAnatomy(FuncAnatomyTest{Name: test.Name, Obj: test.Obj}); len(messages) > 0 {
+		return messages
+	}
+
+	program := test.Program
+	if program == "" {
+		program = "program"
+	}
+
+	oldCommandLine, oldUsage, oldArgs := flag.CommandLine, flag.Usage, os.Args
+
+	defer func() {
+		flag.CommandLine, flag.Usage, os.Args = oldCommandLine, oldUsage, oldArgs
+	}()
+
+	usageCalled := false
+	setup := reflect.ValueOf(test.Obj)
+
+	oc := outputCase{
+		description:  "Function '" + test.Name + "'",
+		ignoreStdout: true,
+		hideExpected: test.HideExpected,
+		timeout:      test.Timeout,
+		isolate:      test.Isolate,
+		expectExit:   test.ExpectExit,
+		exitCode:     test.ExitCode,
+	}
+
+	// A function that ends the program never gets to the checks below,
+	// so its error output is checked like any other output test's
+	if test.ExpectExit {
+		oc.stderrStrings, oc.stderrMatchers = test.ErrorStrings, test.ErrorMatchers
+	}
+
+	// Otherwise the error output is checked along with the flags
+	oc.ignoreStderr = oc.stderrStrings == nil && oc.stderrMatchers == nil
+
+	// Reset the command line after stderr is redirected so the flag package writes to the capture
+	oc.function = reflect.ValueOf(func() {
+
+		// Reading user input gets the end of the input right away
+		if empty, err := os.Open(os.DevNull); err == nil {
+
+			oldStdin := os.Stdin
+			os.Stdin = empty
+
+			defer func() {
+				os.Stdin = oldStdin
+				empty.Close()
+			}()
+		}
+
+		flag.CommandLine = flag.NewFlagSet(program, flag.ContinueOnError)
+		flag.CommandLine.SetOutput(os.Stderr)
+		flag.CommandLine.Usage = func() {
+			usageCalled = true
+			flag.Usage()
+		}
+
+		flag.Usage = defaultFlagUsage
+		os.Args = append([]string{program}, test.Args...)
+
+		setup.Call(nil)
+	})
+
+	stderr := captureStderr()
+	defer stderr.stop()
+
+	// Runs in whichever process called the function (see runIsolatedCase)
+	oc.checkCall = func(result execution) string {
+		return strings.Join(checkParsedFlags(oc, test, usageCalled, stderr.stop()), "\n")
+	}
+
+	return oc.run(0, t)
+}
+
+// Checks the flags once the function has parsed the command line.
+// Returns an error message for each problem found.
+func checkParsedFlags(oc outputCase, test FlagTest, usageCalled bool, errorOutput []string) []string {
+
+	arguments := formatCommandLine(test.Args)
+
+	if !flag.Parsed() {
+		return []string{oc.description + " did not parse the command line arguments (e.g., by calling flag.Parse)."}
+	}
+
+	if usageCalled && !test.ExpectError {
+		return []string{oc.description + " reported an error when parsing valid command line arguments: " + arguments +
+			"\nError output:\n" + strings.Join(errorOutput, "\n")}
+	}
+
+	if !usageCalled && test.ExpectError {
+		return []string{oc.description + " did not report an error when parsing invalid command line arguments: " + arguments}
+	}
+
+	if test.ErrorStrings != nil || test.ErrorMatchers != nil {
+
+		message := oc.checkLines(test.ErrorMatchers, test.ErrorStrings, errorOutput,
+			" displayed unexpected number of error output lines for command line arguments "+arguments+".",
+			" displayed unexpected error output for command line arguments "+arguments+".")
+
+		if message != "" {
+			return []string{message}
+		}
+	}
+
+	var messages []string
+
+	for _, expected := range test.Flags {
+		messages = append(messages, checkFlag(expected, arguments)...)
+	}
+
+	if len(messages) > 0 {
+		return messages
+	}
+
+	if test.UsageStrings != nil || test.UsageMatchers != nil {
+
+		usage := outputCase{
+			description: oc.description + " usage text",
+			function: reflect.ValueOf(func() {
+				flag.CommandLine.SetOutput(os.Stderr) // the new capture
+				flag.CommandLine.Usage()
+			}),
+			ignoreStdout:  true,
+			ignoreReturns: true,
+			hideExpected:  test.HideExpected,
+			timeout:       test.Timeout,
+		}
+
+		stderr := captureStderr()
+		result := usage.execute(0)
+		usageOutput := stderr.stop()
+
+		if messages := usage.check(result); len(messages) > 0 {
+			return messages
+		}
+
+		message := usage.checkLines(test.UsageMatchers, test.UsageStrings, usageOutput,
+			" has unexpected number of lines.",
+			" is not what was expected.")
+
+		if message != "" {
+			return []string{message}
+		}
+	}
+
+	return nil
+}
+
+// Checks a single flag after the command line has been parsed.
+// Returns an error message for each problem found.
+func checkFlag(expected FlagExpectation, arguments string) []string {
+
+	f := flag.Lookup(expected.Name)
+	if f == nil {
+		return []string{"Command line flag \"-" + expected.Name + "\" is not defined."}
+	}
+
+	var messages []string
+	description := "Command line flag \"-" + expected.Name + "\""
+
+	if expected.Value != nil {
+
+		var actual interface{}
+
+		// Flags defined using flag.Var may not have a typed value
+		if getter, ok := f.Value.(flag.Getter); ok {
+			actual = getter.Get()
+		} else {
+			actual, expected.Value = f.Value.String(), fmt.Sprint(expected.Value)
+		}
+
+		if reflect.TypeOf(actual) != reflect.TypeOf(expected.Value) {
+			messages = append(messages, description+" must hold a value of type "+reflect.TypeOf(expected.Value).String()+
+				" (e.g., flag."+flagFunctionName(expected.Value)+"), found "+reflect.TypeOf(actual).String()+".")
+
+		} else if !reflect.DeepEqual(actual, expected.Value) {
+			messages = append(messages, description+" holds an unexpected value after parsing command line arguments "+arguments+"."+
+				"\nExpected: "+formatValue(reflect.ValueOf(expected.Value))+
+				"\nActual:   "+formatValue(reflect.ValueOf(actual)))
+		}
+	}
+
+	if expected.Default != nil && f.DefValue != fmt.Sprint(expected.Default) {
+		messages = append(messages, description+" has default value "+strconv.Quote(f.DefValue)+", expected "+strconv.Quote(fmt.Sprint(expected.Default))+".")
+	}
+
+	if expected.Usage != "" && f.Usage != expected.Usage {
+		messages = append(messages, description+" has usage text "+strconv.Quote(f.Usage)+", expected "+strconv.Quote(expected.Usage)+".")
+	}
+
+	return messages
+}
+
+// Returns the flag package function used to define a flag holding the value
+func flagFunctionName(value interface{}) string {
+
+	switch value.(type) {
+	case int:
+		return "IntVar"
+	case int64:
+		return "Int64Var"
+	case uint:
+		return "UintVar"
+	case uint64:
+		return "Uint64Var"
+	case float64:
+		return "Float64Var"
+	case bool:
+		return "BoolVar"
+	case time.Duration:
+		return "DurationVar"
+	}
+
+	return "StringVar"
+}
+
+// Formats command line arguments the way they would be typed (e.g., -n 5 "two words")
+func formatCommandLine(args []string) string {
+
+	if len(args) == 0 {
+		return "(none)"
+	}
+
+	var quoted []string
+
+	for _, arg := range args {
+
+		if arg == "" || strings.ContainsAny(arg, " \t\"'") {
+			arg = strconv.Quote(arg)
+		}
+
+		quoted = append(quoted, arg)
+	}
+
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,113 @@
+package helpers
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+var flagCount int
+var flagName string
+
+// Defines the flags and parses the command line
+func parseTestFlags() {
+	flag.IntVar(&flagCount, "n", 1, "number of times")
+	flag.StringVar(&flagName, "name", "world", "name to greet")
+	flag.Parse()
+}
+
+// Parses the flags and ends the program if the count isn't positive
+func validatingMain() {
+
+	parseTestFlags()
+
+	if flagCount < 1 {
+		fmt.Fprintln(os.Stderr, "n must be positive")
+		os.Exit(2)
+	}
+}
+
+// Parses the flags and then reads user input until there isn't any more
+func readingMain() {
+
+	parseTestFlags()
+
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+	}
+}
+
+func TestFlags(t *testing.T) {
+
+	tests := []struct {
+		test    FlagTest
+		problem string
+	}{
+		{
+			test: FlagTest{Name: "parseTestFlags", Obj: parseTestFlags, Args: []string{"-n", "5"},
+				Flags: []FlagExpectation{{Name: "n", Value: 5, Default: 1, Usage: "number of times"}, {Name: "name", Value: "world"}}},
+		},
+		{
+			test: FlagTest{Name: "parseTestFlags", Obj: parseTestFlags, Args: []string{"-n", "5"},
+				Flags: []FlagExpectation{{Name: "n", Value: 6}}},
+			problem: "holds an unexpected value after parsing command line arguments -n 5",
+		},
+		{
+			test: FlagTest{Name: "parseTestFlags", Obj: parseTestFlags, Args: []string{"-n", "five"}, ExpectError: true,
+				ErrorStrings: []string{"invalid value \"five\" for flag -n: parse error", "Usage of program:",
+					"-n int", "number of times (default 1)", "-name string", "name to greet (default \"world\")"}},
+		},
+		{
+			test:    FlagTest{Name: "parseTestFlags", Obj: parseTestFlags, Args: []string{"-count", "5"}},
+			problem: "reported an error when parsing valid command line arguments: -count 5",
+		},
+		{
+			test: FlagTest{Name: "parseTestFlags", Obj: parseTestFlags,
+				UsageMatchers: []OutputMatcher{Exact("Usage of tool:"), Exact("-n int"), Exact("number of times (default 1)"),
+					Exact("-name string"), Exact("name to greet (default \"world\")")}, Program: "tool"},
+		},
+		{
+			test: FlagTest{Name: "validatingMain", Obj: validatingMain, Args: []string{"-n", "0"},
+				ExpectExit: true, ExitCode: 2, ErrorStrings: []string{"n must be positive"}},
+		},
+		{
+			test:    FlagTest{Name: "validatingMain", Obj: validatingMain, Args: []string{"-n", "0"}, ExpectExit: true, ExitCode: 1},
+			problem: "ended the program with exit status 2, expected exit status 1",
+		},
+		{
+			test:    FlagTest{Name: "validatingMain", Obj: validatingMain, Args: []string{"-n", "0"}, Isolate: true},
+			problem: "ended the program (e.g., by calling os.Exit or log.Fatal) with exit status 2",
+		},
+		{
+			test: FlagTest{Name: "validatingMain", Obj: validatingMain, Args: []string{"-n", "3"}, Isolate: true,
+				Flags: []FlagExpectation{{Name: "n", Value: 3}}},
+		},
+		{
+			test: FlagTest{Name: "readingMain", Obj: readingMain, Args: []string{"-name", "Go"}, Timeout: time.Second,
+				Flags: []FlagExpectation{{Name: "name", Value: "Go"}}},
+		},
+	}
+
+	commandLine := flag.CommandLine
+
+	for i, test := range tests {
+
+		messages := checkFlags(test.test, t)
+
+		if test.problem == "" {
+			if len(messages) > 0 {
+				t.Errorf("case %d: unexpected failure %v", i+1, messages)
+			}
+		} else if len(messages) == 0 || !strings.Contains(messages[0], test.problem) {
+			t.Errorf("case %d: expected failure containing %q, got %v", i+1, test.problem, messages)
+		}
+
+		if flag.CommandLine != commandLine {
+			t.Errorf("case %d: flag.CommandLine was not restored", i+1)
+		}
+	}
+}
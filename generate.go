@@ -0,0 +1,571 @@
+package helpers
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+)
+
+// Creates a random value for a parameter.
+// size grows as more cases are generated (e.g., longer slices, larger numbers).
+type Generator func(r *rand.Rand, size int) reflect.Value
+
+// Maximum size passed to generators
+const maxGeneratedSize = 20
+
+// Nested values (e.g., a struct holding a pointer to itself) stop being generated at this depth
+const maxGeneratedDepth = 4
+
+// Characters used for generated strings (includes spaces and non-ASCII characters)
+var generatedRunes = []rune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789 .,!?-_éßøñ日本語😀")
+
+// Returns a generator that creates random values of the specified type
+func RandomValues(t reflect.Type) Generator {
+	return func(r *rand.Rand, size int) reflect.Value {
+		return randomValue(t, r, size, 0)
+	}
+}
+
+// Returns a generator that picks one of the provided values
+// (e.g., OneOf(reflect.ValueOf("add"), reflect.ValueOf("remove"))).
+// The values must have the parameter's type (e.g., reflect.ValueOf(int64(5)) for an int64).
+func OneOf(values ...reflect.Value) Generator {
+	return func(r *rand.Rand, size int) reflect.Value {
+		return values[r.Intn(len(values))]
+	}
+}
+
+// Returns a generator that creates ints between min and max (inclusive).
+// Only works for int parameters (use OneOf or a custom Generator for other integer types).
+func IntRange(min int, max int) Generator {
+	return func(r *rand.Rand, size int) reflect.Value {
+		return reflect.ValueOf(min + r.Intn(max-min+1))
+	}
+}
+
+// Creates a random value of the specified type
+func randomValue(t reflect.Type, r *rand.Rand, size int, depth int) reflect.Value {
+
+	value := reflect.New(t).Elem()
+
+	if depth > maxGeneratedDepth {
+		return value
+	}
+
+	switch t.Kind() {
+
+	case reflect.Bool:
+		value.SetBool(r.Intn(2) == 1)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+
+		// Mostly small numbers, with the occasional large one
+		n := r.Int63n(int64(size*size) + 1)
+		if r.Intn(10) == 0 {
+			n = r.Int63()
+		}
+
+		if r.Intn(2) == 0 {
+			n = -n
+		}
+
+		if value.OverflowInt(n) {
+			n %= int64(1) << (t.Bits() - 1)
+		}
+
+		value.SetInt(n)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+
+		n := uint64(r.Int63n(int64(size*size) + 1))
+		if r.Intn(10) == 0 {
+			n = r.Uint64()
+		}
+
+		if value.OverflowUint(n) {
+			n &= uint64(1)<<t.Bits() - 1
+		}
+
+		value.SetUint(n)
+
+	case reflect.Float32, reflect.Float64:
+		value.SetFloat((r.Float64()*2 - 1) * float64(size*size))
+
+	case reflect.Complex64, reflect.Complex128:
+		value.SetComplex(complex((r.Float64()*2-1)*float64(size), (r.Float64()*2-1)*float64(size)))
+
+	case reflect.String:
+
+		runes := make([]rune, r.Intn(size+1))
+		for i := range runes {
+			runes[i] = generatedRunes[r.Intn(len(generatedRunes))]
+		}
+
+		value.SetString(string(runes))
+
+	case reflect.Slice:
+
+		length := r.Intn(size + 1)
+		value.Set(reflect.MakeSlice(t, length, length))
+
+		for i := 0; i < length; i++ {
+			value.Index(i).Set(randomValue(t.Elem(), r, size, depth+1))
+		}
+
+	case reflect.Array:
+
+		for i := 0; i < value.Len(); i++ {
+			value.Index(i).Set(randomValue(t.Elem(), r, size, depth+1))
+		}
+
+	case reflect.Map:
+
+		value.Set(reflect.MakeMap(t))
+
+		for i := r.Intn(size + 1); i > 0; i-- {
+			value.SetMapIndex(randomValue(t.Key(), r, size, depth+1), randomValue(t.Elem(), r, size, depth+1))
+		}
+
+	case reflect.Pointer:
+
+		if r.Intn(5) != 0 {
+			value.Set(reflect.New(t.Elem()))
+			value.Elem().Set(randomValue(t.Elem(), r, size, depth+1))
+		}
+
+	case reflect.Struct:
+
+		// Unexported fields can't be set, so they're left as zero values
+		for i := 0; i < t.NumField(); i++ {
+			if t.Field(i).IsExported() {
+				value.Field(i).Set(randomValue(t.Field(i).Type, r, size, depth+1))
+			}
+		}
+	}
+
+	// Interfaces, functions and channels are left as nil
+	return value
+}
+
+// Returns the edge case values for the specified type
+// (e.g., 0, 1, -1 and the min/max values for ints; "" and non-ASCII text for strings)
+func edgeValues(t reflect.Type, depth int) []reflect.Value {
+
+	zero := reflect.New(t).Elem()
+
+	if depth > maxGeneratedDepth {
+		return []reflect.Value{zero}
+	}
+
+	var values []reflect.Value
+
+	set := func(setValue func(v reflect.Value)) {
+		v := reflect.New(t).Elem()
+		setValue(v)
+		values = append(values, v)
+	}
+
+	switch t.Kind() {
+
+	case reflect.Bool:
+		set(func(v reflect.Value) {})
+		set(func(v reflect.Value) { v.SetBool(true) })
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+
+		max := int64(1)<<(t.Bits()-1) - 1
+
+		for _, n := range []int64{0, 1, -1, max, -max - 1} {
+			n := n
+			set(func(v reflect.Value) { v.SetInt(n) })
+		}
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+
+		max := uint64(math.MaxUint64) >> (64 - t.Bits())
+
+		for _, n := range []uint64{0, 1, max} {
+			n := n
+			set(func(v reflect.Value) { v.SetUint(n) })
+		}
+
+	case reflect.Float32, reflect.Float64:
+
+		max := math.MaxFloat64
+		if t.Kind() == reflect.Float32 {
+			max = math.MaxFloat32
+		}
+
+		for _, f := range []float64{0, 1, -1, 0.5, -max, max} {
+			f := f
+			set(func(v reflect.Value) { v.SetFloat(f) })
+		}
+
+	case reflect.Complex64, reflect.Complex128:
+
+		for _, c := range []complex128{0, 1, 1i, -1 - 1i} {
+			c := c
+			set(func(v reflect.Value) { v.SetComplex(c) })
+		}
+
+	case reflect.String:
+
+		for _, s := range []string{"", " ", "a", "Hello, World!", "héllo wörld", "日本語", "\t\n"} {
+			s := s
+			set(func(v reflect.Value) { v.SetString(s) })
+		}
+
+	case reflect.Slice:
+
+		values = append(values, zero)
+		set(func(v reflect.Value) { v.Set(reflect.MakeSlice(t, 0, 0)) })
+
+		for _, elem := range edgeValues(t.Elem(), depth+1) {
+			elem := elem
+			set(func(v reflect.Value) { v.Set(reflect.Append(reflect.MakeSlice(t, 0, 1), elem)) })
+		}
+
+	case reflect.Map:
+
+		values = append(values, zero)
+		set(func(v reflect.Value) { v.Set(reflect.MakeMap(t)) })
+
+		keys, elems := edgeValues(t.Key(), depth+1), edgeValues(t.Elem(), depth+1)
+
+		for i := range keys {
+			key, elem := keys[i], elems[i%len(elems)]
+			set(func(v reflect.Value) {
+				v.Set(reflect.MakeMap(t))
+				v.SetMapIndex(key, elem)
+			})
+		}
+
+	case reflect.Pointer:
+
+		values = append(values, zero)
+
+		for _, elem := range edgeValues(t.Elem(), depth+1) {
+			elem := elem
+			set(func(v reflect.Value) {
+				v.Set(reflect.New(t.Elem()))
+				v.Elem().Set(elem)
+			})
+		}
+
+	case reflect.Array, reflect.Struct:
+
+		// Use the nth edge value of every element/field for the nth value
+		var parts [][]reflect.Value
+		count := 1
+
+		for i := 0; i < partCount(t); i++ {
+
+			var edges []reflect.Value
+			if t.Kind() == reflect.Array {
+				edges = edgeValues(t.Elem(), depth+1)
+			} else if t.Field(i).IsExported() {
+				edges = edgeValues(t.Field(i).Type, depth+1)
+			}
+
+			parts = append(parts, edges)
+
+			if len(edges) > count {
+				count = len(edges)
+			}
+		}
+
+		for n := 0; n < count; n++ {
+			set(func(v reflect.Value) {
+				for i, edges := range parts {
+					if len(edges) > 0 {
+						part(v, i).Set(edges[n%len(edges)])
+					}
+				}
+			})
+		}
+
+	default:
+		values = append(values, zero)
+	}
+
+	return values
+}
+
+// Returns the number of elements (arrays) or fields (structs) in the type
+func partCount(t reflect.Type) int {
+
+	if t.Kind() == reflect.Array {
+		return t.Len()
+	}
+
+	return t.NumField()
+}
+
+// Returns element (arrays) or field (structs) i of the value
+func part(v reflect.Value, i int) reflect.Value {
+
+	if v.Kind() == reflect.Array {
+		return v.Index(i)
+	}
+
+	return v.Field(i)
+}
+
+// Returns a deep copy of the value so the function being tested
+// can't change the values other calls (or failure messages) use
+func copyValue(v reflect.Value) reflect.Value {
+
+	switch v.Kind() {
+
+	case reflect.Slice:
+
+		if v.IsNil() {
+			return v
+		}
+
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(copyValue(v.Index(i)))
+		}
+
+		return c
+
+	case reflect.Map:
+
+		if v.IsNil() {
+			return v
+		}
+
+		c := reflect.MakeMap(v.Type())
+		for _, key := range v.MapKeys() {
+			c.SetMapIndex(copyValue(key), copyValue(v.MapIndex(key)))
+		}
+
+		return c
+
+	case reflect.Pointer:
+
+		if v.IsNil() {
+			return v
+		}
+
+		c := reflect.New(v.Type().Elem())
+		c.Elem().Set(copyValue(v.Elem()))
+
+		return c
+
+	case reflect.Array, reflect.Struct:
+
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+
+		for i := 0; i < partCount(v.Type()); i++ {
+			if v.Kind() == reflect.Array || v.Type().Field(i).IsExported() {
+				part(c, i).Set(copyValue(part(v, i)))
+			}
+		}
+
+		return c
+	}
+
+	return v
+}
+
+// Returns simpler versions of the value to try when shrinking a failing input
+// (e.g., 0 and n/2 for ints, shorter strings and slices, nil pointers)
+func shrinkCandidates(v reflect.Value) []reflect.Value {
+
+	var candidates []reflect.Value
+
+	add := func(setValue func(c reflect.Value)) {
+		c := reflect.New(v.Type()).Elem()
+		setValue(c)
+		candidates = append(candidates, c)
+	}
+
+	switch v.Kind() {
+
+	case reflect.Bool:
+		if v.Bool() {
+			add(func(c reflect.Value) {})
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+
+		n := v.Int()
+		if n == 0 {
+			break
+		}
+
+		add(func(c reflect.Value) {})
+
+		if n < 0 && n != math.MinInt64 && !v.OverflowInt(-n) {
+			add(func(c reflect.Value) { c.SetInt(-n) })
+		}
+
+		if n/2 != 0 {
+			add(func(c reflect.Value) { c.SetInt(n / 2) })
+		}
+
+		if n > 0 && n-1 != n/2 {
+			add(func(c reflect.Value) { c.SetInt(n - 1) })
+		} else if n < 0 && n+1 != n/2 {
+			add(func(c reflect.Value) { c.SetInt(n + 1) })
+		}
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+
+		n := v.Uint()
+		if n == 0 {
+			break
+		}
+
+		add(func(c reflect.Value) {})
+
+		if n/2 != 0 {
+			add(func(c reflect.Value) { c.SetUint(n / 2) })
+		}
+
+		if n-1 != n/2 {
+			add(func(c reflect.Value) { c.SetUint(n - 1) })
+		}
+
+	case reflect.Float32, reflect.Float64:
+
+		f := v.Float()
+		if f == 0 || math.IsNaN(f) {
+			break
+		}
+
+		add(func(c reflect.Value) {})
+
+		if math.Trunc(f) != f && !math.IsInf(f, 0) {
+			add(func(c reflect.Value) { c.SetFloat(math.Trunc(f)) })
+		}
+
+		if math.Abs(f) > 1 {
+			add(func(c reflect.Value) { c.SetFloat(f / 2) })
+		}
+
+	case reflect.String:
+
+		runes := []rune(v.String())
+		if len(runes) == 0 {
+			break
+		}
+
+		add(func(c reflect.Value) {})
+
+		if len(runes) > 1 {
+			add(func(c reflect.Value) { c.SetString(string(runes[:len(runes)/2])) })
+			add(func(c reflect.Value) { c.SetString(string(runes[len(runes)/2:])) })
+		}
+
+		for i := range runes {
+			if i < maxGeneratedSize {
+				shorter := string(append(append([]rune{}, runes[:i]...), runes[i+1:]...))
+				add(func(c reflect.Value) { c.SetString(shorter) })
+			}
+		}
+
+	case reflect.Slice:
+
+		if v.IsNil() {
+			break
+		}
+
+		if v.Len() == 0 {
+			add(func(c reflect.Value) {})
+			break
+		}
+
+		add(func(c reflect.Value) { c.Set(reflect.MakeSlice(v.Type(), 0, 0)) })
+
+		if v.Len() > 1 {
+			add(func(c reflect.Value) { c.Set(copyValue(v.Slice(0, v.Len()/2))) })
+			add(func(c reflect.Value) { c.Set(copyValue(v.Slice(v.Len()/2, v.Len()))) })
+		}
+
+		for i := 0; i < v.Len() && i < maxGeneratedSize; i++ {
+			i := i
+			add(func(c reflect.Value) {
+				c.Set(reflect.AppendSlice(copyValue(v.Slice(0, i)), copyValue(v.Slice(i+1, v.Len()))))
+			})
+		}
+
+		for i := 0; i < v.Len() && i < maxGeneratedSize; i++ {
+			for _, elem := range shrinkCandidates(v.Index(i)) {
+				i, elem := i, elem
+				add(func(c reflect.Value) {
+					c.Set(copyValue(v))
+					c.Index(i).Set(elem)
+				})
+			}
+		}
+
+	case reflect.Map:
+
+		if v.IsNil() {
+			break
+		}
+
+		if v.Len() == 0 {
+			add(func(c reflect.Value) {})
+			break
+		}
+
+		add(func(c reflect.Value) { c.Set(reflect.MakeMap(v.Type())) })
+
+		for _, key := range sortedMapKeys(v) {
+
+			key := key
+			add(func(c reflect.Value) {
+				c.Set(copyValue(v))
+				c.SetMapIndex(key, reflect.Value{})
+			})
+
+			for _, elem := range shrinkCandidates(v.MapIndex(key)) {
+				elem := elem
+				add(func(c reflect.Value) {
+					c.Set(copyValue(v))
+					c.SetMapIndex(key, elem)
+				})
+			}
+		}
+
+	case reflect.Pointer:
+
+		if v.IsNil() {
+			break
+		}
+
+		add(func(c reflect.Value) {})
+
+		for _, elem := range shrinkCandidates(v.Elem()) {
+			elem := elem
+			add(func(c reflect.Value) {
+				c.Set(reflect.New(v.Type().Elem()))
+				c.Elem().Set(elem)
+			})
+		}
+
+	case reflect.Array, reflect.Struct:
+
+		for i := 0; i < partCount(v.Type()); i++ {
+
+			if v.Kind() == reflect.Struct && !v.Type().Field(i).IsExported() {
+				continue
+			}
+
+			for _, elem := range shrinkCandidates(part(v, i)) {
+				i, elem := i, elem
+				add(func(c reflect.Value) {
+					c.Set(copyValue(v))
+					part(c, i).Set(elem)
+				})
+			}
+		}
+	}
+
+	return candidates
+}
@@ -1,99 +1,121 @@
 package helpers
 
 import (
-	"context"
-	"math/rand"
 	"reflect"
-	"regexp"
 	"strconv"
 	"strings"
 	"testing"
 	"time"
-
-	"github.com/savantes1/outcap"
 )
 
 // Function anatomy testing struct
+// Variadic = true if the final parameter is expected to be variadic (e.g., "nums ...int").
+// The final ArgTypes entry for a variadic function is the slice type (e.g., []int).
+// Points/Category = weight and category used for scoring (see Scorecard; 0 Points = DefaultPoints)
+// Ungraded = true if the check is reported but worth no points (Points is ignored)
 type FuncAnatomyTest struct {
 	Name		string
 	Obj			interface{}
 	ArgTypes	[]reflect.Type
 	ReturnTypes []reflect.Type
+	Variadic	bool
+	Points		float64
+	Ungraded	bool
+	Category	string
 }
 
 // Runs standard function anatomy tests
 func RunFunctionAnatomyTests(testFuncs []FuncAnatomyTest, t *testing.T) {
+	RunFunctionAnatomyTestsWithMode(testFuncs, DefaultFailureMode, t)
+}
 
-	// If a test failure has already occurred, no need to run further tests
-	if !t.Failed() {
+// Runs standard function anatomy tests using the specified failure mode
+func RunFunctionAnatomyTestsWithMode(testFuncs []FuncAnatomyTest, mode FailureMode, t *testing.T) {
 
-		for i := 0; i < len(testFuncs); i++ {
+	// If a test failure has already occurred, no need to report further problems.
+	// The tests are still run so they can be scored.
+	report := !t.Failed()
 
-			function := reflect.ValueOf(testFuncs[i].Obj)
+	for i := 0; i < len(testFuncs); i++ {
 
-			if function.IsValid() {
+		test := testFuncs[i]
 
-				if function.Type().NumIn() == len(testFuncs[i].ArgTypes) {
+		runCase(t, mode, test.Name, report, func(t *testing.T) ScoreEntry {
+			return newScoreEntry(test.Name, test.Category, "Anatomy", test.Points, test.Ungraded, checkFunctionAnatomy(test))
+		})
+	}
+}
 
-					// make a slice of all expected parameter types
-					var expectedParamTypes []string
-					for j := 0; j < len(testFuncs[i].ArgTypes); j++ {
-						expectedParamTypes = append(expectedParamTypes, "Position " + strconv.Itoa(j) + ": " + testFuncs[i].ArgTypes[j].String())
-					}
+// Runs the function anatomy test.
+// Returns an error message for each problem found.
+func checkFunctionAnatomy(test FuncAnatomyTest) []string {
 
-					for j := 0; j < len(testFuncs[i].ArgTypes); j++ {
+	var messages []string
 
-						param := function.Type().In(j)
+	function := reflect.ValueOf(test.Obj)
 
-						if param != testFuncs[i].ArgTypes[j] {
-							t.Error("Function '" + testFuncs[i].Name + "' has unexpected parameter type at position " +
-								strconv.Itoa(j) + ". Expected type " + testFuncs[i].ArgTypes[j].String() +
-								", found type " + param.String() + ".\nExpected function parameter types:\n" + strings.Join(expectedParamTypes, "\n"))
-							
-							break
-						}
+	if function.IsValid() {
 
-					}
+		if function.Type().NumIn() == len(test.ArgTypes) {
+
+			// make a slice of all expected parameter types
+			var expectedParamTypes []string
+			for j := 0; j < len(test.ArgTypes); j++ {
+				expectedParamTypes = append(expectedParamTypes, "Position " + strconv.Itoa(j) + ": " + test.ArgTypes[j].String())
+			}
+
+			for j := 0; j < len(test.ArgTypes); j++ {
 
-				} else {
-					t.Error("Function '" + testFuncs[i].Name +
-						"' has unexpected number of parameters. Expected " + strconv.Itoa(len(testFuncs[i].ArgTypes)) +
-						" parameter(s), found " + strconv.Itoa(function.Type().NumIn()) + " parameter(s)")
+				param := function.Type().In(j)
+
+				if param != test.ArgTypes[j] {
+					messages = append(messages, "Function '" + test.Name + "' has unexpected parameter type at position " +
+						strconv.Itoa(j) + ". Expected type " + test.ArgTypes[j].String() +
+						", found type " + param.String() + ".\nExpected function parameter types:\n" + strings.Join(expectedParamTypes, "\n"))
+
+					break
 				}
 
-				if !t.Failed() {
-					if function.Type().NumOut() == len(testFuncs[i].ReturnTypes) {
+			}
 
-						for j := 0; j < len(testFuncs[i].ReturnTypes); j++ {
+			if len(messages) == 0 && function.Type().IsVariadic() != test.Variadic {
+				messages = append(messages, variadicErrorMessage("Function '" + test.Name + "'", test.Variadic))
+			}
 
-							returnParam := function.Type().Out(j)
+		} else {
+			messages = append(messages, "Function '" + test.Name +
+				"' has unexpected number of parameters. Expected " + strconv.Itoa(len(test.ArgTypes)) +
+				" parameter(s), found " + strconv.Itoa(function.Type().NumIn()) + " parameter(s)")
+		}
 
-							if returnParam != testFuncs[i].ReturnTypes[j] {
+		if len(messages) == 0 {
+			if function.Type().NumOut() == len(test.ReturnTypes) {
 
-								// t.Error("Function '" + testFuncs[i].Name +
-								// 	"' returned unexpected data type for return " + strconv.Itoa(j) + ". Expected type " +
-								// 	testFuncs[i].ReturnTypes[j].String() + ", received type " + returnParam.String())
+				for j := 0; j < len(test.ReturnTypes); j++ {
 
-								t.Error("Function '" + testFuncs[i].Name +
-									"' returned unexpected data type. Expected type " +
-									testFuncs[i].ReturnTypes[j].String() + ", received type " + returnParam.String())
-							}
-						}
+					returnParam := function.Type().Out(j)
 
-					} else {
+					if returnParam != test.ReturnTypes[j] {
 
-						t.Error("Function '" + testFuncs[i].Name +
-							"' returns unexpected number of values. Expected " + strconv.Itoa(len(testFuncs[i].ReturnTypes)) +
-							" value(s), found " + strconv.Itoa(function.Type().NumOut())  + " value(s)")
+						messages = append(messages, "Function '" + test.Name +
+							"' returned unexpected data type. Expected type " +
+							test.ReturnTypes[j].String() + ", received type " + returnParam.String())
 					}
 				}
 
 			} else {
-				t.Error("'" + testFuncs[i].Name + "' function definition missing.")
-			}
 
+				messages = append(messages, "Function '" + test.Name +
+					"' returns unexpected number of values. Expected " + strconv.Itoa(len(test.ReturnTypes)) +
+					" value(s), found " + strconv.Itoa(function.Type().NumOut())  + " value(s)")
+			}
 		}
+
+	} else {
+		messages = append(messages, "'" + test.Name + "' function definition missing.")
 	}
+
+	return messages
 }
 
 
@@ -105,15 +127,54 @@ func RunFunctionAnatomyTests(testFuncs []FuncAnatomyTest, t *testing.T) {
 
 
 // Function output testing struct
+// Variadic = true if the function has a variadic final parameter. When true, the
+// final Args value must be a slice holding all of the variadic arguments
+// (e.g., reflect.ValueOf([]int{1, 2, 3}) for "Sum(nums ...int)")
+// Timeout = amount of time the function is given to complete (0 = DefaultTimeout)
+// Isolate = true to run the function in a separate process (see IsolateTests)
+// ExpectExit = true if the function should end the program (e.g., os.Exit or log.Fatal) with ExitCode
+// as the exit status. These tests are always run in a separate process.
+// Important: a function that unexpectedly ends the program with a non-zero exit status (e.g., os.Exit(1)
+// or log.Fatal) ends the whole test binary, losing every other result, unless the test runs in a
+// separate process (Isolate, ExpectExit or IsolateTests). os.Exit(0) is caught either way under go test.
+// HideExpected = true to leave the expected output out of failure messages (e.g., hidden tests)
+// ReturnDetail = amount of detail shown when a return value doesn't match (see Detail)
+// StdoutMatchers = used instead of StdoutStrings when set (e.g., Regex, Contains, FloatWithin)
+// StderrStrings/StderrMatchers = expected error output (os.Stderr, the log package and println).
+// Stderr is only checked when one of them is set (or CheckStderr is true) and IgnoreStderr is false.
+// ReturnComparators = comparator for each return position (nil entries use reflect.DeepEqual)
+// ReturnErrorMatchers = used instead of the comparator for each return position holding an error
+// (e.g., ErrorIs(ErrNotFound); nil entries aren't used). ErrorValue(nil) creates an error typed Returns value.
+// ExpectPanic = true if the call should panic. PanicMatcher checks the panic value (nil = any value).
+// Points/Category = weight and category used for scoring (see Scorecard; 0 Points = DefaultPoints)
+// Ungraded = true if the check is reported but worth no points (Points is ignored)
 type FuncOutputTest struct {
 	Name          string
 	Obj           interface{}
 	Args          []reflect.Value
+	Variadic      bool
 	StdinStrings  []string
 	IgnoreStdout  bool
 	StdoutStrings []string
+	StdoutMatchers []OutputMatcher
+	IgnoreStderr  bool
+	StderrStrings []string
+	StderrMatchers []OutputMatcher
+	HideExpected  bool
 	IgnoreReturns bool
 	Returns       []reflect.Value
+	ReturnComparators []Comparator
+	ReturnErrorMatchers []ErrorMatcher
+	ExpectPanic   bool
+	PanicMatcher  ErrorMatcher
+	ReturnDetail  Detail
+	Timeout       time.Duration
+	Isolate       bool
+	ExpectExit    bool
+	ExitCode      int
+	Points        float64
+	Ungraded      bool
+	Category      string
 }
 
 // Converts FuncOutputTest object to FuccAnatomyTest object
@@ -136,15 +197,39 @@ func convertFuncOutputTestToAnatomyTest(ot FuncOutputTest) FuncAnatomyTest {
 		Obj: ot.Obj,
 		ArgTypes: argTypes,
 		ReturnTypes: returnTypes,
+		Variadic: ot.Variadic,
+	}
+}
+
+// Builds the error message used when a function/method is (or isn't) unexpectedly variadic
+func variadicErrorMessage(description string, expectVariadic bool) string {
+
+	if expectVariadic {
+		return description + " must be variadic. The final parameter should be declared using \"...\" (e.g., \"nums ...int\")"
+	}
+
+	return description + " must not be variadic. The final parameter should not be declared using \"...\""
+}
+
+// Calls the function using the provided arguments.
+// If variadic is true, the final argument must be a slice
+// holding the values for the variadic parameter.
+func callFunction(function reflect.Value, args []reflect.Value, variadic bool) []reflect.Value {
+
+	if variadic {
+		return function.CallSlice(args)
 	}
+
+	return function.Call(args)
 }
 
 // This function is meant to be used by all unit tests in order to gracefully recover from
-// runtime errors and provide a standard error message
+// runtime errors and provide a standard error message.
+// The message includes the panic value and where it occurred in the submission (see RuntimeErrorAudience).
 func StandardRunTimeErrorCheck(t *testing.T) {
 	err := recover()
 	if err != nil {
-		t.Error("A runtime error occurred while attempting to run this unit test.\nThere are a variety of situations that can cause runtime errors (e.g., accessing array index out of range, dereferencing a nil pointer, etc.).\nReview your code and/or contact the instructor for assistance.")
+		t.Error(runtimeErrorReport(err))
 	}
 }
 
@@ -152,147 +237,59 @@ func StandardRunTimeErrorCheck(t *testing.T) {
 
 // Runs standard function output tests using provided values
 func RunFunctionOutputTests(testFuncs []FuncOutputTest, randomSeed int64, t *testing.T) {
+	RunFunctionOutputTestsWithMode(testFuncs, randomSeed, DefaultFailureMode, t)
+}
 
-	// If a test failure has already occurred, no need to run further tests
-	if !t.Failed() {
-
-		for i := 0; i < len(testFuncs); i++ {
-
-			// Run the anatomy test on the function first
-			RunFunctionAnatomyTests([]FuncAnatomyTest{ convertFuncOutputTestToAnatomyTest(testFuncs[i])}, t)
-
-			// Don't even bother running the actual output tests if the anatomy tests failed
-			if !t.Failed() {
-
-
-				function := reflect.ValueOf(testFuncs[i].Obj)
-
-				if function.IsValid() {
-
-					//TODO: handle errors, maybe?
-					c, _ := outcap.NewContainer('\n')
-
-					// Create context for goroutine with timeout in case the function
-					// tries to process more stdin input than what is expected. Running
-					// in goroutine with context allows for easily timing out after 3 seconds.
-					ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
-
-					var returnVals []reflect.Value
-
-					go func() {
-
-						//Note: defer statements execute in LIFO order
-						
-						// The cancel function needs to be called once the 
-						// goroutine has completely finished. This is how the
-						// context object knows how it finished (i.e., timed out or normal)
-						defer func() {
-							cancel()
-						}()
-
-						// Handle any runtime errors that may have occurred
-						defer StandardRunTimeErrorCheck(t)
-
-						// In case this function uses random numbers, make sure to set
-						// the seed to specified seed value so the "random" numbers will 
-						// be predictable (deterministic) and will match the expected output
-						rand.Seed(randomSeed)
-
-						// Call the function...
-						// Note: This logic does not support variadic functions!
-						returnVals = function.Call(testFuncs[i].Args)
-
-					}()
-
-					// Write each input string to function
-					for _, s := range testFuncs[i].StdinStrings {
-						c.WriteToStdin(s)
-					}
-
-					// Wait for goroutine to finish
-					select {
-					case <-ctx.Done():
-						c.Stop() // stop redirecting stdin/stdout
-					}
-
-					// If method timed out, it probably means that there was an unexpected fmt.Scanln()
-					if ctx.Err() == context.DeadlineExceeded {
-
-						t.Error("Function '" + testFuncs[i].Name +
-							"' timed out before completing. Most likely issue is that the program is calling fmt.Scanln too many times.")
-
-					} else {
-
-						// A runtime error could have caused an error, so check for that before proceeding
-						if !t.Failed() {
-
-							if !testFuncs[i].IgnoreReturns {
-
-								for j := 0; j < len(testFuncs[i].Returns); j++ {
-
-									// For testing logic
-									//fmt.Println("Expected:", testObjs[i].Returns[j].Interface(), "Actual:", returnVals[j].Interface())
-
-									//if testFuncs[i].Returns[j].Interface() != returnVals[j].Interface() {
-									if !reflect.DeepEqual(testFuncs[i].Returns[j].Interface(), returnVals[j].Interface()) {
-
-										// t.Error("Function '" + testFuncs[i].Name +
-										// 	"' returned unexpected value. Specifically, return value position " + strconv.Itoa(j) + ".")
-
-										t.Error("Function '" + testFuncs[i].Name + "' returned unexpected value. This means that the value (not type) that was returned after calling the function did not match what was expected, given the arguments passed to the function or data supplied by the user. Be sure to test your function using many different input values to make sure it works in all scenarios.")
-									
-										// Added break so only one error is returned
-										break
-									}
-								}
-							}
-
-							if !testFuncs[i].IgnoreStdout && !t.Failed() {
-
-								if len(testFuncs[i].StdoutStrings) == len(c.OutData) {
-
-									for j := 0; j < len(testFuncs[i].StdoutStrings); j++ {
-		
-										if testFuncs[i].StdoutStrings[j] != strings.TrimSpace(c.OutData[j]) {
-
-											t.Error("Function '" + testFuncs[i].Name +
-												"' displayed unexpected output to the terminal. Unexpected output line: " + strconv.Itoa(j+1) + "\nCommon output problems to double check: misspellings, incorrect character case, extra spaces")
-
-											// Added break so only one error is returned
-											break
-				
-										}
-									}
-
-								} else {
-
-									// For testing
-									// for _, line := range c.OutData {
-									// 	fmt.Println(line)
-									// }
-
-
-									t.Error("Function '" + testFuncs[i].Name +
-										"' displayed unexpected number of output lines to the terminal. Expected " + 
-										strconv.Itoa(len(testFuncs[i].StdoutStrings)) +
-										" line(s), found " + strconv.Itoa(len(c.OutData))  + " line(s)")
-								}
-							}
+// Runs standard function output tests using provided values and the specified failure mode
+func RunFunctionOutputTestsWithMode(testFuncs []FuncOutputTest, randomSeed int64, mode FailureMode, t *testing.T) {
 
-						}
+	for i := 0; i < len(testFuncs); i++ {
 
-					}
+		test := testFuncs[i]
 
-				} else {
-					t.Error("'" + testFuncs[i].Name + "' function definition missing.")
-				}
-
-			}
+		// If a test failure has already occurred, no need to report further problems.
+		// The tests are still run so they can be scored.
+		runCase(t, mode, test.Name, !t.Failed(), func(t *testing.T) ScoreEntry {
+			return outputScoreEntry(test.Name, test.Category, test.Points, test.Ungraded, test.HideExpected, checkFunctionOutput(test, randomSeed, t))
+		})
+	}
+}
 
-		}
+// Runs the function output test.
+// Returns an error message for each problem found.
+func checkFunctionOutput(test FuncOutputTest, randomSeed int64, t *testing.T) []string {
 
+	// Run the anatomy test on the function first.
+	// Don't even bother running the actual output tests if the anatomy tests failed
+	if messages := checkFunctionAnatomy(convertFuncOutputTestToAnatomyTest(test)); len(messages) > 0 {
+		return messages
 	}
 
+	return outputCase{
+		description: "Function '" + test.Name + "'",
+		function: reflect.ValueOf(test.Obj),
+		args: test.Args,
+		variadic: test.Variadic,
+		stdinStrings: test.StdinStrings,
+		ignoreStdout: test.IgnoreStdout,
+		stdoutStrings: test.StdoutStrings,
+		stdoutMatchers: test.StdoutMatchers,
+		ignoreStderr: test.IgnoreStderr,
+		stderrStrings: test.StderrStrings,
+		stderrMatchers: test.StderrMatchers,
+		hideExpected: test.HideExpected,
+		ignoreReturns: test.IgnoreReturns,
+		returns: test.Returns,
+		returnDetail: test.ReturnDetail,
+		comparators: test.ReturnComparators,
+		errorMatchers: test.ReturnErrorMatchers,
+		expectPanic: test.ExpectPanic,
+		panicMatcher: test.PanicMatcher,
+		timeout: test.Timeout,
+		isolate: test.Isolate,
+		expectExit: test.ExpectExit,
+		exitCode: test.ExitCode,
+	}.run(randomSeed, t)
 }
 
 
@@ -302,20 +299,50 @@ func RunFunctionOutputTests(testFuncs []FuncOutputTest, randomSeed int64, t *tes
 
 
 // Method anatomy testing struct
+// Variadic = true if the final parameter is expected to be variadic (e.g., "nums ...int").
+// The final ArgTypes entry for a variadic method is the slice type (e.g., []int).
+// Receiver = kind of receiver the method must be declared with (AnyReceiver = not checked)
+// Points/Category = weight and category used for scoring (see Scorecard; 0 Points = DefaultPoints)
+// Ungraded = true if the check is reported but worth no points (Points is ignored)
 type MethodAnatomyTest struct {
 	Name         string
 	ArgTypes     []reflect.Type
 	ReturnTypes  []reflect.Type
+	Variadic     bool
+	Receiver     ReceiverKind
+	Points       float64
+	Ungraded     bool
+	Category     string
 }
 
 
 
 // Runs standard struct method anatomy test using provided values.
 // Returns true if anatomy passes tests. Otherwise returns false.
-// IMPORTANT: testObject must be a pointer to the struct object being tested!
+// testObject = the object being tested or a pointer to it
 func RunMethodAnatomyTest(testObject interface{}, methodTest MethodAnatomyTest, t *testing.T) bool {
+	return runMethodAnatomyTest(testObject, methodTest, DefaultFailureMode, t)
+}
+
+// Runs the struct method anatomy test using the specified failure mode.
+// Returns true if anatomy passes tests. Otherwise returns false.
+func runMethodAnatomyTest(testObject interface{}, methodTest MethodAnatomyTest, mode FailureMode, t *testing.T) bool {
+
+	name := objectTypeName(testObject) + "." + methodTest.Name
+
+	return runCase(t, mode, name, true, func(t *testing.T) ScoreEntry {
+		return newScoreEntry(name, methodTest.Category, "Anatomy", methodTest.Points, methodTest.Ungraded, checkMethodAnatomy(testObject, methodTest))
+	})
+}
+
+// Runs the struct method anatomy test.
+// Returns an error message for each problem found.
+func checkMethodAnatomy(testObject interface{}, methodTest MethodAnatomyTest) []string {
+
+	var messages []string
 
-	passedTests := true
+	// The pointer's method set includes the methods declared with either kind of receiver
+	testObject = objectPointer(testObject)
 
 	method := reflect.ValueOf(testObject).MethodByName(methodTest.Name)
 
@@ -335,26 +362,27 @@ func RunMethodAnatomyTest(testObject interface{}, methodTest MethodAnatomyTest,
 
 				if param != methodTest.ArgTypes[j] {
 
-					t.Error(reflect.TypeOf(testObject).Elem().Name() + " method '" + methodTest.Name +
+					messages = append(messages, objectTypeName(testObject) + " method '" + methodTest.Name +
 						"' has unexpected parameter type at position " + strconv.Itoa(j) + ". Expected type " +
 						methodTest.ArgTypes[j].String() + ", found type " + param.String() + ".\nExpected method parameter types:\n" + strings.Join(expectedParamTypes, "\n"))
-					
-					passedTests = false
 
 					break
 				}
 			}
 
+			if len(messages) == 0 && method.Type().IsVariadic() != methodTest.Variadic {
+				messages = append(messages, variadicErrorMessage(objectTypeName(testObject) + " method '" + methodTest.Name + "'", methodTest.Variadic))
+			}
+
 		} else {
 
-			t.Error(reflect.TypeOf(testObject).Elem().Name() + " method '" + methodTest.Name +
+			messages = append(messages, objectTypeName(testObject) + " method '" + methodTest.Name +
 				"' has unexpected number of parameters. Expected " + strconv.Itoa(len(methodTest.ArgTypes)) +
 				" parameter(s), found " + strconv.Itoa(method.Type().NumIn()) + " parameter(s)")
-
-			passedTests = false
 		}
 
-		if !t.Failed() {
+		// Only check the return types if this method's parameters are correct
+		if len(messages) == 0 {
 			if method.Type().NumOut() == len(methodTest.ReturnTypes) {
 
 				for j := 0; j < len(methodTest.ReturnTypes); j++ {
@@ -363,53 +391,102 @@ func RunMethodAnatomyTest(testObject interface{}, methodTest MethodAnatomyTest,
 
 					if param != methodTest.ReturnTypes[j] {
 
-						t.Error(reflect.TypeOf(testObject).Elem().Name() + " method '" + methodTest.Name +
+						messages = append(messages, objectTypeName(testObject) + " method '" + methodTest.Name +
 							"' returned unexpected data type. Expected type " +
 							methodTest.ReturnTypes[j].String() + ", received type " + param.String())
-						
-						passedTests = false
 					}
 
 				}
 
 			} else {
-				t.Error(reflect.TypeOf(testObject).Elem().Name() + " method '" + methodTest.Name +
+				messages = append(messages, objectTypeName(testObject) + " method '" + methodTest.Name +
 					"' returns unexpected number of values. Expected " + strconv.Itoa(len(methodTest.ReturnTypes)) +
 					" value(s), received " + strconv.Itoa(method.Type().NumOut()) + " value(s)")
-
-				passedTests = false
 			}
 		}
 
 	} else {
-		t.Error(reflect.TypeOf(testObject).Elem().Name() + " struct definition missing '" + methodTest.Name + "' method")
-		passedTests = false
+		messages = append(messages, objectTypeName(testObject) + " struct definition missing '" + methodTest.Name + "' method")
 	}
 
-	return passedTests
+	// Only check the receiver if the method's signature is correct
+	if len(messages) == 0 {
+		if message := checkReceiver(testObject, methodTest.Name, methodTest.Receiver); message != "" {
+			messages = append(messages, message)
+		}
+	}
 
+	return messages
 }
 
 
 // Runs standard struct method anatomy tests using provided values
-// IMPORTANT: testObject must be a pointer to the struct object being tested!
+// testObject = the object being tested or a pointer to it
 func RunMethodAnatomyTests(testObject interface{}, methodTests []MethodAnatomyTest, t *testing.T) {
+	RunMethodAnatomyTestsWithMode(testObject, methodTests, DefaultFailureMode, t)
+}
+
+// Runs standard struct method anatomy tests using provided values and the specified failure mode
+// testObject = the object being tested or a pointer to it
+func RunMethodAnatomyTestsWithMode(testObject interface{}, methodTests []MethodAnatomyTest, mode FailureMode, t *testing.T) {
 
 	for i := 0; i < len(methodTests); i++ {
-		RunMethodAnatomyTest(testObject, methodTests[i], t)
+		runMethodAnatomyTest(testObject, methodTests[i], mode, t)
 	}
 }
 
 
 // Method output testing struct
+// Variadic = true if the method has a variadic final parameter. When true, the
+// final Args value must be a slice holding all of the variadic arguments
+// Timeout = amount of time the method is given to complete (0 = DefaultTimeout)
+// Isolate = true to run the method in a separate process (see IsolateTests).
+// Once the method completes it's called again in this process to update the object's state,
+// so its side effects (e.g., appending to a file) happen twice.
+// ExpectExit = true if the method should end the program (e.g., os.Exit or log.Fatal) with ExitCode
+// as the exit status. These tests are always run in a separate process.
+// Important: a method that unexpectedly ends the program with a non-zero exit status (e.g., os.Exit(1)
+// or log.Fatal) ends the whole test binary, losing every other result, unless the test runs in a
+// separate process (Isolate, ExpectExit or IsolateTests). os.Exit(0) is caught either way under go test.
+// HideExpected = true to leave the expected output out of failure messages (e.g., hidden tests)
+// ReturnDetail = amount of detail shown when a return value doesn't match (see Detail)
+// StdoutMatchers = used instead of StdoutStrings when set (e.g., Regex, Contains, FloatWithin)
+// StderrStrings/StderrMatchers = expected error output (os.Stderr, the log package and println).
+// Stderr is only checked when one of them is set (or CheckStderr is true) and IgnoreStderr is false.
+// ReturnComparators = comparator for each return position (nil entries use reflect.DeepEqual)
+// ReturnErrorMatchers = used instead of the comparator for each return position holding an error
+// (e.g., ErrorIs(ErrNotFound); nil entries aren't used). ErrorValue(nil) creates an error typed Returns value.
+// ExpectPanic = true if the call should panic. PanicMatcher checks the panic value (nil = any value).
+// Receiver = kind of receiver the method must be declared with (AnyReceiver = not checked)
+// Points/Category = weight and category used for scoring (see Scorecard; 0 Points = DefaultPoints)
+// Ungraded = true if the check is reported but worth no points (Points is ignored)
 type MethodOutputTest struct {
 	Name          string
 	Args          []reflect.Value
+	Variadic      bool
+	Receiver      ReceiverKind
 	StdinStrings  []string
 	IgnoreStdout  bool
 	StdoutStrings []string
+	StdoutMatchers []OutputMatcher
+	IgnoreStderr  bool
+	StderrStrings []string
+	StderrMatchers []OutputMatcher
+	HideExpected  bool
 	IgnoreReturns bool
 	Returns       []reflect.Value
+	ReturnComparators []Comparator
+	ReturnErrorMatchers []ErrorMatcher
+	ExpectPanic   bool
+	PanicMatcher  ErrorMatcher
+	ReturnDetail  Detail
+	Timeout       time.Duration
+	Isolate       bool
+	ExpectExit    bool
+	ExitCode      int
+	Points        float64
+	Ungraded      bool
+	Category      string
 }
 
 
@@ -433,6 +510,8 @@ func convertMethodOutputTestToAnatomyTest(ot MethodOutputTest) MethodAnatomyTest
 		Name: ot.Name,
 		ArgTypes: argTypes,
 		ReturnTypes: returnTypes,
+		Variadic: ot.Variadic,
+		Receiver: ot.Receiver,
 	}
 }
 
@@ -440,148 +519,85 @@ func convertMethodOutputTestToAnatomyTest(ot MethodOutputTest) MethodAnatomyTest
 
 // Runs standard struct method output test.
 // Returns provided object after method has been invoked for further evaluation.  
-// IMPORTANT: testObject must be a pointer to the struct object being tested!
+// testObject = the object being tested or a pointer to it. A value is copied, so the
+// method can't change the caller's value (the returned object holds the changes).
 func RunMethodOutputTest(testObject interface{}, methodTest MethodOutputTest, randomSeed int64, t *testing.T) reflect.Value {
+	return runMethodOutputTest(testObject, methodTest, randomSeed, DefaultFailureMode, t)
+}
 
-	// If a test failure has already occurred, no need to run further tests
-	if !t.Failed() {
-
-		// Run the anatomy test on the method first.
-		// Don't even bother running the actual output tests if the anatomy tests failed
-		if RunMethodAnatomyTest(testObject, convertMethodOutputTestToAnatomyTest(methodTest), t) {
-
-			method := reflect.ValueOf(testObject).MethodByName(methodTest.Name)
-
-			if method.IsValid() {
-
-				//TODO: handle errors, maybe?
-				c, _ := outcap.NewContainer('\n')
-
-				// Create context for goroutine with timeout in case the method
-				// tries to process more stdin input than what is expected. Running
-				// in goroutine with context allows for easily timing out after 3 seconds.
-				ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
-
-				var returnVals []reflect.Value
-
-				go func() {
-
-					//Note: defer statements execute in LIFO order
-						
-					// The cancel function needs to be called once the 
-					// goroutine has completely finished. This is how the
-					// context object knows how it finished (i.e., timed out or normal)
-					defer func() {
-						cancel()
-					}()
-
-					// Handle any runtime errors that may have occurred
-					defer StandardRunTimeErrorCheck(t)
-
-					// In case this method uses random numbers, make sure to set
-					// the seed to specified seed value so the "random" numbers will 
-					// be predictable (deterministic) and will match the expected 
-					// output in the methodReturns values
-					rand.Seed(randomSeed)
-
-					// Call the method...
-					// Note: This logic does not support variadic functions!
-					returnVals = method.Call(methodTest.Args)
-
-				}()
-
-				// Write each input string to method
-				for _, s := range methodTest.StdinStrings {
-					c.WriteToStdin(s)
-				}
-
-				select {
-				case <-ctx.Done():
-					c.Stop() // stop redirecting stdin/stdout
-				}
-
-				// If method timed out, it probably means that there was an unexpected fmt.Scanln()
-				if ctx.Err() == context.DeadlineExceeded {
-
-					t.Error(reflect.TypeOf(testObject).Elem().Name() + " method '" + methodTest.Name +
-						"' timed out before completing. Most likely issue is that the program is calling fmt.Scanln too many times.")
-
-				} else {
-
-					// A runtime error could have caused an error, so check for that before proceeding
-					if !t.Failed() {
-
-						if !methodTest.IgnoreReturns {
-
-							for j := 0; j < len(methodTest.Returns); j++ {
-
-								// For testing logic
-								//fmt.Println("Expected:", methodReturns[i][j].Interface(), "Actual:", returnVals[j].Interface())
-
-								//if methodTest.Returns[j].Interface() != returnVals[j].Interface() {
-								if !reflect.DeepEqual(methodTest.Returns[j].Interface(), returnVals[j].Interface()) {
-
-									t.Error(reflect.TypeOf(testObject).Elem().Name() + " method '" + methodTest.Name + "' returned unexpected value. This means that the value (not type) that was returned after calling the function did not match what was expected, given the arguments passed to the function or data supplied by the user. Be sure to test your function using many different input values to make sure it works in all scenarios.")
-									break
-								}
-
-							}
-						}
-
-
-
-
-						if !methodTest.IgnoreStdout && !t.Failed() {
-
-							if len(methodTest.StdoutStrings) == len(c.OutData) {
-
-								for j := 0; j < len(methodTest.StdoutStrings); j++ {
-			
-									if methodTest.StdoutStrings[j] != strings.TrimSpace(c.OutData[j]) {
+// Runs the struct method output test using the specified failure mode.
+// Returns provided object after method has been invoked for further evaluation.
+func runMethodOutputTest(testObject interface{}, methodTest MethodOutputTest, randomSeed int64, mode FailureMode, t *testing.T) reflect.Value {
 
-										t.Error(reflect.TypeOf(testObject).Elem().Name() + " method '" + methodTest.Name +
-												"' displayed unexpected output to the terminal. Unexpected output line: " + strconv.Itoa(j+1) + "\nCommon output problems to double check: misspellings, incorrect character case, extra spaces")
+	testObject = objectPointer(testObject)
 
-										
-										break
-									}
-								}
+	name := objectTypeName(testObject) + "." + methodTest.Name
 
-							} else {
+	// If a test failure has already occurred, no need to report further problems.
+	// The test is still run so it can be scored.
+	runCase(t, mode, name, !t.Failed(), func(t *testing.T) ScoreEntry {
+		return outputScoreEntry(name, methodTest.Category, methodTest.Points, methodTest.Ungraded, methodTest.HideExpected, checkMethodOutput(testObject, methodTest, randomSeed, t))
+	})
 
-								// For testing
-								// for _, line := range c.OutData {
-								// 	fmt.Println(line)
-								// }
+	return reflect.ValueOf(testObject)
 
+}
 
-								t.Error(reflect.TypeOf(testObject).Elem().Name() + " method '" + methodTest.Name +
-									"' displayed unexpected number of output lines to the terminal. Expected " + 
-									strconv.Itoa(len(methodTest.StdoutStrings)) +
-									" line(s), found " + strconv.Itoa(len(c.OutData))  + " line(s)")
-							}
-						}
-					}				
-				}
+// Runs the struct method output test.
+// Returns an error message for each problem found.
+func checkMethodOutput(testObject interface{}, methodTest MethodOutputTest, randomSeed int64, t *testing.T) []string {
 
-			} else {
-				t.Error(reflect.TypeOf(testObject).Elem().Name() + " struct definition missing '" + methodTest.Name + "' method")
-			}
-		}
+	// Run the anatomy test on the method first.
+	// Don't even bother running the actual output tests if the anatomy tests failed
+	if messages := checkMethodAnatomy(testObject, convertMethodOutputTestToAnatomyTest(methodTest)); len(messages) > 0 {
+		return messages
 	}
-	
-	return reflect.ValueOf(testObject)
 
+	return outputCase{
+		description: objectTypeName(testObject) + " method '" + methodTest.Name + "'",
+		function: reflect.ValueOf(testObject).MethodByName(methodTest.Name),
+		args: methodTest.Args,
+		variadic: methodTest.Variadic,
+		stdinStrings: methodTest.StdinStrings,
+		ignoreStdout: methodTest.IgnoreStdout,
+		stdoutStrings: methodTest.StdoutStrings,
+		stdoutMatchers: methodTest.StdoutMatchers,
+		ignoreStderr: methodTest.IgnoreStderr,
+		stderrStrings: methodTest.StderrStrings,
+		stderrMatchers: methodTest.StderrMatchers,
+		hideExpected: methodTest.HideExpected,
+		ignoreReturns: methodTest.IgnoreReturns,
+		returns: methodTest.Returns,
+		returnDetail: methodTest.ReturnDetail,
+		comparators: methodTest.ReturnComparators,
+		errorMatchers: methodTest.ReturnErrorMatchers,
+		expectPanic: methodTest.ExpectPanic,
+		panicMatcher: methodTest.PanicMatcher,
+		timeout: methodTest.Timeout,
+		isolate: methodTest.Isolate,
+		expectExit: methodTest.ExpectExit,
+		exitCode: methodTest.ExitCode,
+		keepsState: true,
+	}.run(randomSeed, t)
 }
 
 
 // Runs standard struct method output tests using provided values
-// IMPORTANT: testObject must be a pointer to the struct object being tested!
+// testObject = the object being tested or a pointer to it. A value is copied (once,
+// so the methods see each other's changes), so the caller's value isn't changed.
 func RunMethodOutputTests(testObject interface{}, methodTests []MethodOutputTest, randomSeed int64, t *testing.T) {
+	RunMethodOutputTestsWithMode(testObject, methodTests, randomSeed, DefaultFailureMode, t)
+}
+
+// Runs standard struct method output tests using provided values and the specified failure mode
+// testObject = the object being tested or a pointer to it (see RunMethodOutputTests)
+func RunMethodOutputTestsWithMode(testObject interface{}, methodTests []MethodOutputTest, randomSeed int64, mode FailureMode, t *testing.T) {
+
+	testObject = objectPointer(testObject)
 
 	for i := 0; i < len(methodTests); i++ {
 
-		RunMethodOutputTest(testObject, methodTests[i], randomSeed, t)
+		runMethodOutputTest(testObject, methodTests[i], randomSeed, mode, t)
 	}
 
 }
@@ -592,39 +608,26 @@ func RunMethodOutputTests(testObject interface{}, methodTests []MethodOutputTest
 // Removes all comments from the specified text
 // and returns filtered text
 func RemoveAllComments(text string) string {
-
-	// match all comment blocks
-	re := regexp.MustCompile(`(?s)/\*.*?\*/`)
-
-	filteredText := re.ReplaceAllString(string(text), "")
-
-	// match all single line comments
-	re = regexp.MustCompile(`//.*`)
-
-	return re.ReplaceAllString(filteredText, "")
+	return removeComments(text)
 }
 
 // Tests specified code text to confirm using proper
 // random number seeding template
 func RunRandomNumberTemplateTest(text string, t *testing.T) {
 
-	filteredText := RemoveAllComments(text)
-
-	// regex: \s = [\t\n\f\r ]
-	// regex: \S = [^\t\n\f\r ]
+	var messages []string
 
-	matched, _ := regexp.MatchString(`(?s)func[ ]+init[ ]*\([ ]*\)[ ]*\{\s*rand.Seed\(int64\(time.Now\(\).Nanosecond\(\)\)\)\s*\}`, filteredText)
+	source, _ := ParseSource(text)
 
-	if !matched {
-		t.Error("Program doesn't seed random number generator as demonstrated in \"Random Numbers\" example code")
+	if !source.HasRandomSeedTemplate() {
+		messages = append(messages, "Program doesn't seed random number generator as demonstrated in \"Random Numbers\" example code")
 	}
 
-	re := regexp.MustCompile(`rand.Seed`)
-	matches := re.FindAllStringSubmatch(filteredText, -1)
-
-	if len(matches) > 1 {
-		t.Error("Random number generator seeded more than once")
+	if len(source.PackageCalls("rand", "Seed")) > 1 {
+		messages = append(messages, "Random number generator seeded more than once")
 	}
+
+	recordResult(t, newScoreEntry("Random number seeding", "", "Static", 0, false, messages), true)
 }
 
 
@@ -641,22 +644,13 @@ func RunInstantiateObjectsTest(text string, objectName string, minObjectCount in
 // the specified objects are instantiated in the code.
 func RunInstantiateObjectsTestWithFunctionName(text string, objectName string, minObjectCount int, maxObjectCount int, objInstantiatedFuncName string, t *testing.T) {
 
-	filteredText := RemoveAllComments(text)
-
-	// keep track of the number of objects instantiated
-	var objectCounter int = 0
-
-	// find all of the objects instantiated the traditional way
-	re := regexp.MustCompile(`var[ ]+[\w]+[ ]+` + objectName)
-	matches := re.FindAllStringSubmatch(filteredText, -1)
+	var messages []string
 
-	objectCounter += len(matches)
+	source, _ := ParseSource(text)
 
-	// find all of the objects instantiated using the short syntax
-	re = regexp.MustCompile(`(?s):=[ ]*`+objectName+`[ ]*\{.*?\}`)
-	matches = re.FindAllStringSubmatch(filteredText, -1)
-
-	objectCounter += len(matches)
+	// keep track of the number of objects instantiated
+	// (limited to the specified function if it's declared in the text)
+	objectCounter := source.CountInstantiations(objectName, objInstantiatedFuncName)
 
 	// Figure out if there are too many or too few of the instantiated objects
 	if minObjectCount == maxObjectCount {
@@ -667,7 +661,7 @@ func RunInstantiateObjectsTestWithFunctionName(text string, objectName string, m
 				errorMessage += " in function \"" + objInstantiatedFuncName + "\""
 			}
 
-			t.Error(errorMessage)
+			messages = append(messages, errorMessage)
 		}
 	} else {
 
@@ -678,7 +672,7 @@ func RunInstantiateObjectsTestWithFunctionName(text string, objectName string, m
 				errorMessage += " in function \"" + objInstantiatedFuncName + "\""
 			}
 
-			t.Error(errorMessage)
+			messages = append(messages, errorMessage)
 		}
 
 		if objectCounter > maxObjectCount {
@@ -688,61 +682,21 @@ func RunInstantiateObjectsTestWithFunctionName(text string, objectName string, m
 				errorMessage += " in function \"" + objInstantiatedFuncName + "\""
 			}
 
-			t.Error(errorMessage)
+			messages = append(messages, errorMessage)
 		}
 	}
+
+	recordResult(t, newScoreEntry("Instantiate " + objectName, "", "Static", 0, false, messages), true)
 }
 
 // Returns the specified function body text.
 // First return value = true if function was found, otherwise false
 // Second return value = function body text
 func GetFunctionBodyText(text string, functionName string) (bool, string) {
-	
-	// remove any comments first
-	noCommentsText := RemoveAllComments(text)
-
-	re := regexp.MustCompile(`(?s)func[^\n]*`+functionName+`[^\n]*\([^\n]*\)[^\n]*\{`)
-	
-	functionSignatureIndexes := re.FindStringIndex(noCommentsText)
-
-	// FindStringIndex will be nil if no match found
-	// This means the function name wasn't found
-	if functionSignatureIndexes == nil {
-		return false, ""
-	}
-	
-	// Count the number curly braces
-	// We start at one for the opening curly brace for the function
-	curlyBraceCount := 1
-
-	// Remove all text before the function opening curly brace
-	filteredText := noCommentsText[functionSignatureIndexes[1]:]
 
-	var functionEndingIndex int
+	source, _ := ParseSource(text)
 
-	// Loop through each character in the text
-	// counting each opening and closing curly brace
-	for i, ch := range filteredText {
-
-		// rune 123 = '{'
-		if ch == 123 {
-			curlyBraceCount += 1
-		}
-
-		// rune 125 = '}'
-		if ch == 125 {
-			curlyBraceCount -= 1
-		}
-
-		// This only hits 0 when function closing
-		// curly brace is hit
-		if curlyBraceCount == 0 {
-			functionEndingIndex = i
-			break
-		}
-	}
-
-	return true, filteredText[:functionEndingIndex]
+	return source.FunctionBody(functionName)
 }
 
 
@@ -759,9 +713,10 @@ const (
 
 // Tests specified code text to make sure specified command 
 // line flags are mapped to a variable of the appropriate type
+// (see RunFlagTests to check how the flags behave at runtime)
 func RunValidateFlagArgTest(text string, flagType FlagType, flagName string, t *testing.T) {
 
-	filteredText := RemoveAllComments(text)
+	source, _ := ParseSource(text)
 
 	var functionName string 
 	switch flagType {
@@ -777,11 +732,14 @@ func RunValidateFlagArgTest(text string, flagType FlagType, flagName string, t *
 
 	if len(functionName) > 0 {
 
-		re := regexp.MustCompile(`flag[\t ]*\.[\t ]*`+functionName+`[\t ]*\(.+?,[\t ]*"`+flagName+`"`)
-		if !re.MatchString(filteredText) {
-			t.Error("Must use \"flag\" package to map \""+flagName+"\" command line argument")
+		var messages []string
+
+		if !source.HasFlagVar(functionName, flagName) {
+			messages = append(messages, "Must use \"flag\" package to map \""+flagName+"\" command line argument")
 		}
 
+		recordResult(t, newScoreEntry("Flag " + flagName, "", "Static", 0, false, messages), true)
+
 	} else {
 		panic("Unexpected Flag Type")
 	}
@@ -0,0 +1,438 @@
+package helpers
+
+import (
+	"math/rand"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+	"unicode"
+)
+
+// Kinds of interaction script steps
+type stepKind int
+
+const (
+	expectStep stepKind = iota
+	expectRegexStep
+	sendStep
+	expectEOFStep
+)
+
+// A single step of an interaction script (see Expect, ExpectRegex, Send and ExpectEOF)
+type InteractionStep struct {
+	kind    stepKind
+	text    string
+	pattern *regexp.Regexp
+}
+
+// The next output must be the specified text, followed by whitespace or the end of the
+// output so far. Leading/trailing whitespace (including line breaks) is ignored, so prompts
+// that don't end with a new line (e.g., fmt.Print("Enter a number: ")) can be matched
+// before any input is sent.
+func Expect(text string) InteractionStep {
+	return InteractionStep{kind: expectStep, text: text}
+}
+
+// The next line of output must match the regular expression.
+// The expression must match the whole line (it's anchored at both ends).
+func ExpectRegex(pattern string) InteractionStep {
+	return InteractionStep{kind: expectRegexStep, text: pattern, pattern: regexp.MustCompile(`^(?:` + pattern + `)$`)}
+}
+
+// Sends a line of user input
+func Send(line string) InteractionStep {
+	return InteractionStep{kind: sendStep, text: line}
+}
+
+// The function must finish without displaying any more output
+func ExpectEOF() InteractionStep {
+	return InteractionStep{kind: expectEOFStep}
+}
+
+// Describes the step (used in failure messages)
+func (s InteractionStep) String() string {
+
+	switch s.kind {
+	case expectStep:
+		return "expect " + strconv.Quote(strings.TrimSpace(s.text))
+	case expectRegexStep:
+		return "expect line matching /" + s.text + "/"
+	case sendStep:
+		return "send " + strconv.Quote(s.text)
+	}
+
+	return "expect end of program"
+}
+
+// Function interaction testing struct.
+// Script steps are run in order while the function is running: each Send is only
+// written to stdin once every earlier Expect has matched, so prompts are verified
+// to appear before the input is read.
+// Timeout = amount of time each step waits for output (and the function is given to finish) (0 = DefaultTimeout)
+// IgnoreReturns/Returns/ReturnComparators = return values checked once the function finishes
+// Points/Category = weight and category used for scoring (see Scorecard; 0 Points = DefaultPoints)
+// Ungraded = true if the check is reported but worth no points (Points is ignored)
+type FuncInteractionTest struct {
+	Name              string
+	Obj               interface{}
+	Args              []reflect.Value
+	Variadic          bool
+	Script            []InteractionStep
+	IgnoreReturns     bool
+	Returns           []reflect.Value
+	ReturnComparators []Comparator
+	Timeout           time.Duration
+	Points            float64
+	Ungraded          bool
+	Category          string
+}
+
+// Method interaction testing struct (see FuncInteractionTest)
+type MethodInteractionTest struct {
+	Name              string
+	Args              []reflect.Value
+	Variadic          bool
+	Script            []InteractionStep
+	IgnoreReturns     bool
+	Returns           []reflect.Value
+	ReturnComparators []Comparator
+	Timeout           time.Duration
+	Points            float64
+	Ungraded          bool
+	Category          string
+}
+
+// Runs function interaction tests
+func RunFunctionInteractionTests(testFuncs []FuncInteractionTest, randomSeed int64, t *testing.T) {
+	RunFunctionInteractionTestsWithMode(testFuncs, randomSeed, DefaultFailureMode, t)
+}
+
+// Runs function interaction tests using the specified failure mode
+func RunFunctionInteractionTestsWithMode(testFuncs []FuncInteractionTest, randomSeed int64, mode FailureMode, t *testing.T) {
+
+	for _, test := range testFuncs {
+
+		// If a test failure has already occurred, no need to report further problems.
+		// The tests are still run so they can be scored.
+		runCase(t, mode, test.Name, !t.Failed(), func(t *testing.T) ScoreEntry {
+			return newScoreEntry(test.Name, test.Category, "Interaction", test.Points, test.Ungraded, checkFunctionInteraction(test, randomSeed))
+		})
+	}
+}
+
+// Runs the function interaction test.
+// Returns an error message for each problem found.
+func checkFunctionInteraction(test FuncInteractionTest, randomSeed int64) []string {
+
+	anatomy := FuncAnatomyTest{
+		Name:        test.Name,
+		Obj:         test.Obj,
+		ArgTypes:    valueTypes(test.Args),
+		ReturnTypes: valueTypes(test.Returns),
+		Variadic:    test.Variadic,
+	}
+
+	// Don't even bother running the script if the anatomy tests failed
+	if messages := checkFunctionAnatomy(anatomy); len(messages) > 0 {
+		return messages
+	}
+
+	return interaction{
+		outputCase: outputCase{
+			description:   "Function '" + test.Name + "'",
+			function:      reflect.ValueOf(test.Obj),
+			args:          test.Args,
+			variadic:      test.Variadic,
+			ignoreStdout:  true,
+			ignoreReturns: test.IgnoreReturns,
+			returns:       test.Returns,
+			comparators:   test.ReturnComparators,
+			timeout:       test.Timeout,
+		},
+		script: test.Script,
+	}.run(randomSeed)
+}
+
+// Runs method interaction tests
+// testObject = the object being tested or a pointer to it (see RunMethodOutputTests)
+func RunMethodInteractionTests(testObject interface{}, methodTests []MethodInteractionTest, randomSeed int64, t *testing.T) {
+	RunMethodInteractionTestsWithMode(testObject, methodTests, randomSeed, DefaultFailureMode, t)
+}
+
+// Runs method interaction tests using the specified failure mode
+// testObject = the object being tested or a pointer to it (see RunMethodOutputTests)
+func RunMethodInteractionTestsWithMode(testObject interface{}, methodTests []MethodInteractionTest, randomSeed int64, mode FailureMode, t *testing.T) {
+
+	testObject = objectPointer(testObject)
+
+	for _, test := range methodTests {
+
+		name := objectTypeName(testObject) + "." + test.Name
+
+		// If a test failure has already occurred, no need to report further problems.
+		// The tests are still run so they can be scored.
+		runCase(t, mode, name, !t.Failed(), func(t *testing.T) ScoreEntry {
+			return newScoreEntry(name, test.Category, "Interaction", test.Points, test.Ungraded, checkMethodInteraction(testObject, test, randomSeed))
+		})
+	}
+}
+
+// Runs the method interaction test.
+// Returns an error message for each problem found.
+func checkMethodInteraction(testObject interface{}, test MethodInteractionTest, randomSeed int64) []string {
+
+	anatomy := MethodAnatomyTest{
+		Name:        test.Name,
+		ArgTypes:    valueTypes(test.Args),
+		ReturnTypes: valueTypes(test.Returns),
+		Variadic:    test.Variadic,
+	}
+
+	// Don't even bother running the script if the anatomy tests failed
+	if messages := checkMethodAnatomy(testObject, anatomy); len(messages) > 0 {
+		return messages
+	}
+
+	return interaction{
+		outputCase: outputCase{
+			description:   objectTypeName(testObject) + " method '" + test.Name + "'",
+			function:      reflect.ValueOf(testObject).MethodByName(test.Name),
+			args:          test.Args,
+			variadic:      test.Variadic,
+			ignoreStdout:  true,
+			ignoreReturns: test.IgnoreReturns,
+			returns:       test.Returns,
+			comparators:   test.ReturnComparators,
+			timeout:       test.Timeout,
+		},
+		script: test.Script,
+	}.run(randomSeed)
+}
+
+// Returns the type of each value
+func valueTypes(values []reflect.Value) []reflect.Type {
+
+	var types []reflect.Type
+
+	for _, v := range values {
+		types = append(types, v.Type())
+	}
+
+	return types
+}
+
+// Interaction script run against a function.
+// The returns are checked using the output case once the function finishes.
+type interaction struct {
+	outputCase
+	script []InteractionStep
+}
+
+// Result of comparing an expect step to the output that hasn't been matched yet
+type stepMatch int
+
+const (
+	stepWaiting stepMatch = iota // more output is needed to tell
+	stepMatched
+	stepMismatched
+)
+
+// Compares the step to the unmatched output.
+// closed = true if the function has finished and all of its output has been read.
+// Returns the result and the number of bytes of output the step matched.
+func (s InteractionStep) match(output string, closed bool) (stepMatch, int) {
+
+	remaining := strings.TrimLeft(output, " \t\r\n")
+	skipped := len(output) - len(remaining)
+
+	switch s.kind {
+
+	case expectStep:
+
+		expected := strings.TrimSpace(s.text)
+
+		if strings.HasPrefix(remaining, expected) {
+
+			// The text must be followed by whitespace or the end of the output
+			// (e.g., "Total: 5" doesn't match "Total: 50")
+			rest := remaining[len(expected):]
+			if rest == "" || unicode.IsSpace(rune(rest[0])) {
+				return stepMatched, skipped + len(expected)
+			}
+
+			return stepMismatched, 0
+		}
+
+		if strings.HasPrefix(expected, remaining) && !closed {
+			return stepWaiting, 0
+		}
+
+	case expectRegexStep:
+
+		line := remaining
+
+		if end := strings.IndexByte(remaining, '\n'); end >= 0 {
+			line = remaining[:end]
+		} else if !closed {
+			return stepWaiting, 0
+		}
+
+		if s.pattern.MatchString(strings.TrimSpace(line)) {
+			return stepMatched, skipped + len(line)
+		}
+
+	case expectEOFStep:
+
+		if remaining == "" {
+			if closed {
+				return stepMatched, len(output)
+			}
+
+			return stepWaiting, 0
+		}
+	}
+
+	return stepMismatched, 0
+}
+
+// Runs the function while stepping through the script.
+// Returns an error message for each problem found.
+func (in interaction) run(randomSeed int64) []string {
+
+	c, err := startConsole()
+	if err != nil {
+		return []string{"Unable to redirect stdin/stdout: " + err.Error()}
+	}
+
+	defer c.stop()
+
+	// Buffered so an abandoned goroutine never blocks forever
+	done := make(chan execution, 1)
+
+	go func() {
+
+		var result execution
+
+		// Handle any runtime errors that may have occurred
+		defer func() {
+			if err := recover(); err != nil {
+				result.panicked = true
+				result.panicReport = runtimeErrorReport(err)
+			}
+
+			done <- result
+		}()
+
+		rand.Seed(randomSeed)
+
+		result.returnVals = callFunction(in.function, in.args, in.variadic)
+		result.completed = true
+	}()
+
+	var result execution
+	finished := false
+	consumed := 0
+
+	var conversation []string
+
+	// Waits for something to happen (output or the function finishing).
+	// Returns false if the timeout expired first.
+	wait := func(timeout <-chan time.Time) bool {
+
+		select {
+		case <-c.notify:
+		case result = <-done:
+			finished = true
+			c.stop() // all of the output has now been read
+		case <-timeout:
+			return false
+		}
+
+		return true
+	}
+
+	for i, step := range in.script {
+
+		if step.kind == sendStep {
+			c.send(step.text)
+			conversation = append(conversation, "input:  "+strconv.Quote(step.text))
+			continue
+		}
+
+		timer := time.NewTimer(scaledTimeout(in.timeout))
+
+		for {
+			output, closed := c.snapshot()
+			unmatched := output[consumed:]
+
+			status, length := step.match(unmatched, closed)
+
+			if status == stepMatched {
+				conversation = append(conversation, "output: "+strconv.Quote(unmatched[:length]))
+				consumed += length
+				break
+			}
+
+			var problem string
+
+			if status == stepMismatched {
+
+				switch {
+				case step.kind == expectEOFStep:
+					problem = "displayed unexpected extra output"
+				case closed && strings.TrimSpace(unmatched) == "":
+					problem = "ended before displaying the expected output"
+				default:
+					problem = "displayed unexpected output"
+				}
+
+			} else if !wait(timer.C) {
+
+				if step.kind == expectEOFStep {
+					problem = "did not finish in time (waited " + scaledTimeout(in.timeout).String() + "). Most likely issue is that the program is calling fmt.Scanln too many times"
+				} else {
+					problem = "did not display the expected output in time (waited " + scaledTimeout(in.timeout).String() + ")"
+				}
+
+			} else {
+				continue
+			}
+
+			timer.Stop()
+
+			if finished && result.panicked {
+				return []string{result.runtimeErrorMessage() + "\n" + conversationSoFar(conversation)}
+			}
+
+			return []string{in.description + " " + problem + " at step " + strconv.Itoa(i+1) + " of the interaction script (" + step.String() + ")." +
+				"\nActual output: " + strconv.Quote(strings.TrimLeft(unmatched, " \t\r\n")) +
+				"\n" + conversationSoFar(conversation)}
+		}
+
+		timer.Stop()
+	}
+
+	if !finished {
+
+		select {
+		case result = <-done:
+		case <-time.After(scaledTimeout(in.timeout)):
+			result.timedOut = true
+		}
+	}
+
+	return in.check(result)
+}
+
+// Describes the conversation up to the step that failed
+func conversationSoFar(conversation []string) string {
+
+	if len(conversation) == 0 {
+		return "Conversation so far: (nothing)"
+	}
+
+	return "Conversation so far:\n  " + strings.Join(conversation, "\n  ")
+}
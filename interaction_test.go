@@ -0,0 +1,134 @@
+package helpers
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInteractionStepMatch(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		step   InteractionStep
+		output string
+		closed bool
+		status stepMatch
+		length int
+	}{
+		{"expect prompt", Expect("Enter a number: "), "Enter a number: ", false, stepMatched, 15},
+		{"expect line", Expect("Total: 5"), "Total: 5\n", false, stepMatched, 8},
+		{"expect skips leading whitespace", Expect("Total: 5"), "\n  Total: 5\n", false, stepMatched, 11},
+		{"expect at end of output", Expect("Total: 5"), "Total: 5", true, stepMatched, 8},
+		{"expect needs a boundary", Expect("Total: 5"), "Total: 50\n", false, stepMismatched, 0},
+		{"expect needs a boundary at the end", Expect("Total: 5"), "Total: 50", true, stepMismatched, 0},
+		{"expect partial output", Expect("Total: 5"), "Tot", false, stepWaiting, 0},
+		{"expect partial output after finishing", Expect("Total: 5"), "Tot", true, stepMismatched, 0},
+		{"expect different output", Expect("Total: 5"), "Sum: 5\n", false, stepMismatched, 0},
+		{"regex line", ExpectRegex(`Total: \d+`), "Total: 50\nnext", false, stepMatched, 9},
+		{"regex incomplete line", ExpectRegex(`Total: \d+`), "Total: 5", false, stepWaiting, 0},
+		{"regex last line", ExpectRegex(`Total: \d+`), "Total: 5", true, stepMatched, 8},
+		{"regex mismatch", ExpectRegex(`Total: \d+`), "Total: x\n", false, stepMismatched, 0},
+		{"eof", ExpectEOF(), " \n", true, stepMatched, 2},
+		{"eof while running", ExpectEOF(), "", false, stepWaiting, 0},
+		{"eof with extra output", ExpectEOF(), "extra", true, stepMismatched, 0},
+	}
+
+	for _, test := range tests {
+
+		status, length := test.step.match(test.output, test.closed)
+
+		if status != test.status || length != test.length {
+			t.Errorf("%s: match(%q, %v) = %v, %d, expected %v, %d",
+				test.name, test.output, test.closed, status, length, test.status, test.length)
+		}
+	}
+}
+
+// Prompts for numbers until a positive one is entered (0 if there's no more input)
+func promptPositive() int {
+
+	for {
+		var n int
+
+		fmt.Print("Enter a positive number: ")
+		if _, err := fmt.Scanln(&n); err != nil {
+			return 0
+		}
+
+		if n > 0 {
+			fmt.Println("Total:", n*10)
+			return n
+		}
+
+		fmt.Println("Invalid number")
+	}
+}
+
+func TestFunctionInteraction(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		script  []InteractionStep
+		returns int
+		problem string
+	}{
+		{
+			name: "reprompts after invalid input",
+			script: []InteractionStep{
+				Expect("Enter a positive number:"), Send("-1"),
+				Expect("Invalid number"),
+				Expect("Enter a positive number:"), Send("5"),
+				Expect("Total: 50"), ExpectEOF(),
+			},
+			returns: 5,
+		},
+		{
+			name: "number followed by more digits",
+			script: []InteractionStep{
+				Expect("Enter a positive number:"), Send("5"),
+				Expect("Total: 5"),
+			},
+			returns: 5,
+			problem: "displayed unexpected output at step 3 of the interaction script (expect \"Total: 5\")",
+		},
+		{
+			name: "missing prompt",
+			script: []InteractionStep{
+				Expect("Enter a number:"), Send("5"),
+			},
+			returns: 5,
+			problem: "at step 1 of the interaction script",
+		},
+		{
+			name: "wrong return value",
+			script: []InteractionStep{
+				Expect("Enter a positive number:"), Send("5"),
+				Expect("Total: 50"), ExpectEOF(),
+			},
+			returns: 6,
+			problem: "returned unexpected value",
+		},
+	}
+
+	for _, test := range tests {
+
+		messages := checkFunctionInteraction(FuncInteractionTest{
+			Name:    "promptPositive",
+			Obj:     promptPositive,
+			Script:  test.script,
+			Returns: []reflect.Value{reflect.ValueOf(test.returns)},
+			Timeout: 2 * time.Second,
+		}, 0)
+
+		if test.problem == "" {
+			if len(messages) > 0 {
+				t.Errorf("%s: unexpected failure %v", test.name, messages)
+			}
+		} else if len(messages) == 0 || !strings.Contains(messages[0], test.problem) {
+			t.Errorf("%s: expected failure containing %q, got %v", test.name, test.problem, messages)
+		}
+	}
+}
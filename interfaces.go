@@ -0,0 +1,119 @@
+package helpers
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Interface satisfaction testing struct
+// Name = name of the type being tested (used in messages)
+// Obj = value of the type. Pass a value (e.g., Account{}) when the value type must implement
+// the interface or a pointer (e.g., &Account{}) when only the pointer type has to.
+// Interface = interface the type must implement (see InterfaceType)
+// Points/Category = weight and category used for scoring (see Scorecard; 0 Points = DefaultPoints)
+// Ungraded = true if the check is reported but worth no points (Points is ignored)
+type InterfaceTest struct {
+	Name      string
+	Obj       interface{}
+	Interface reflect.Type
+	Points    float64
+	Ungraded  bool
+	Category  string
+}
+
+// Returns the interface type I (e.g., InterfaceType[fmt.Stringer]() or InterfaceType[sort.Interface]())
+func InterfaceType[I any]() reflect.Type {
+
+	interfaceType := reflect.TypeOf((*I)(nil)).Elem()
+
+	if interfaceType.Kind() != reflect.Interface {
+		panic("InterfaceType requires an interface type, found " + interfaceType.String())
+	}
+
+	return interfaceType
+}
+
+// Runs interface satisfaction tests
+func RunInterfaceTests(tests []InterfaceTest, t *testing.T) {
+	RunInterfaceTestsWithMode(tests, DefaultFailureMode, t)
+}
+
+// Runs interface satisfaction tests using the specified failure mode
+func RunInterfaceTestsWithMode(tests []InterfaceTest, mode FailureMode, t *testing.T) {
+
+	// If a test failure has already occurred, no need to report further problems.
+	// The tests are still run so they can be scored.
+	report := !t.Failed()
+
+	for _, test := range tests {
+
+		if test.Interface == nil || test.Interface.Kind() != reflect.Interface {
+			t.Fatal("Interface test for '" + test.Name + "' needs an interface type (see InterfaceType)")
+		}
+
+		name := test.Name + " implements " + test.Interface.String()
+
+		runCase(t, mode, name, report, func(t *testing.T) ScoreEntry {
+			return newScoreEntry(name, test.Category, "Anatomy", test.Points, test.Ungraded, checkInterface(test))
+		})
+	}
+}
+
+// Runs the interface satisfaction test.
+// Returns an error message for each problem found.
+func checkInterface(test InterfaceTest) []string {
+
+	objType := reflect.TypeOf(test.Obj)
+	if objType == nil {
+		return []string{"'" + test.Name + "' type definition missing."}
+	}
+
+	if objType.Implements(test.Interface) {
+		return nil
+	}
+
+	// The method anatomy checks use a pointer since its method set includes
+	// the methods declared with value and pointer receivers
+	baseType := objType
+	if objType.Kind() == reflect.Pointer {
+		baseType = objType.Elem()
+	}
+
+	pointer := reflect.New(baseType).Interface()
+
+	messages := []string{test.Name + " does not implement " + test.Interface.String() + "."}
+
+	for i := 0; i < test.Interface.NumMethod(); i++ {
+
+		method := test.Interface.Method(i)
+
+		if problems := checkMethodAnatomy(pointer, interfaceMethodAnatomy(method)); len(problems) > 0 {
+			messages = append(messages, problems...)
+			continue
+		}
+
+		if _, ok := objType.MethodByName(method.Name); !ok {
+			messages = append(messages, test.Name+" method '"+method.Name+"' is only implemented on the pointer type *"+baseType.Name()+
+				" (it uses a pointer receiver), so "+baseType.Name()+" values don't implement "+test.Interface.String()+
+				". Either use a value receiver or use *"+baseType.Name()+" values.")
+		}
+	}
+
+	return messages
+}
+
+// Builds the method anatomy test for an interface method
+func interfaceMethodAnatomy(method reflect.Method) MethodAnatomyTest {
+
+	anatomy := MethodAnatomyTest{Name: method.Name, Variadic: method.Type.IsVariadic()}
+
+	for i := 0; i < method.Type.NumIn(); i++ {
+		anatomy.ArgTypes = append(anatomy.ArgTypes, method.Type.In(i))
+	}
+
+	for i := 0; i < method.Type.NumOut(); i++ {
+		anatomy.ReturnTypes = append(anatomy.ReturnTypes, method.Type.Out(i))
+	}
+
+	return anatomy
+}
@@ -0,0 +1,423 @@
+package helpers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// When true, every function/method output test is run in a separate child
+// process (a re-exec of the test binary) so a submission that times out,
+// loops forever or blocks on stdin can be killed without affecting later tests.
+// Individual tests can also be isolated using their Isolate field.
+// Important: once an isolated method completes, it's called again in this process
+// so the object is left in the state the method put it in (the state can't be sent
+// back from the child process). Any side effects (e.g., appending to a file) happen twice.
+// Only isolated tests can contain a submission that unexpectedly ends the program
+// with a non-zero exit status (e.g., os.Exit(1) or log.Fatal). Without isolation it
+// ends the whole test binary and every other result is lost.
+// Initialized from the IsolateEnvVar environment variable (if set).
+var IsolateTests = false
+
+// Environment variable used to initialize IsolateTests (e.g., HELPERS_ISOLATE=1)
+const IsolateEnvVar = "HELPERS_ISOLATE"
+
+// Amount of time an isolated child process is given to start up and reach
+// the test case it was asked to run (scaled by TimeoutScale).
+// The child replays everything that ran before the case in the same top level test,
+// so it's also given the time each earlier case took (or, for isolated cases, their timeout).
+// Code outside the runners (e.g., setup code in the test function) has to fit in this time.
+var IsolationStartupTimeout = 10 * time.Second
+
+// Environment variable used to tell a child process which case to run
+const isolatedCaseEnvVar = "HELPERS_ISOLATED_CASE"
+
+// Environment variable used to tell a child process which earlier cases not to replay
+// (cases that didn't complete, so the parent process didn't replay them either)
+const isolatedSkipEnvVar = "HELPERS_ISOLATED_SKIP"
+
+// File descriptors the child process uses to send results back to the parent.
+// The case's stdout and stderr are sent straight to the parent so any output
+// written before the program ends (e.g., by os.Exit) isn't lost.
+const (
+	isolatedResultFd = 3
+	isolatedStdoutFd = 4
+	isolatedStderrFd = 5
+)
+
+// Files wrapping the file descriptors above (only opened in a child process).
+// They're opened once and stay referenced: an *os.File closes its file descriptor
+// when it's garbage collected, so a temporary wrapper could close one still in use.
+var isolatedFiles struct {
+	result *os.File
+	stdout *os.File
+	stderr *os.File
+}
+
+// Start of the message a child process writes to its stderr when it can't send
+// its results to the parent (the parent collects the child's own output)
+const isolatedSendError = "Unable to send isolated test results: "
+
+func init() {
+
+	if value, ok := os.LookupEnv(IsolateEnvVar); ok {
+
+		isolate, err := strconv.ParseBool(value)
+
+		if err != nil {
+			invalidSetting(IsolateEnvVar, value, "true or false")
+		} else {
+			IsolateTests = isolate
+		}
+	}
+
+	if _, ok := os.LookupEnv(isolatedCaseEnvVar); ok {
+		isolatedFiles.result = os.NewFile(isolatedResultFd, "isolated-result")
+		isolatedFiles.stdout = os.NewFile(isolatedStdoutFd, "isolated-stdout")
+		isolatedFiles.stderr = os.NewFile(isolatedStderrFd, "isolated-stderr")
+	}
+}
+
+// Message sent from an isolated child process to the parent.
+// Once the case finishes, the child sends everything about the
+// result except the output (which the parent already has).
+type isolatedEvent struct {
+	Started       bool   `json:",omitempty"`
+	Finished      bool   `json:",omitempty"`
+	Completed     bool   `json:",omitempty"`
+	Panicked      bool   `json:",omitempty"`
+	PanicReport   string `json:",omitempty"`
+	TimedOut      bool   `json:",omitempty"`
+	Exited        bool   `json:",omitempty"`
+	ExitCode      int    `json:",omitempty"`
+	ReturnMessage string `json:",omitempty"` // return values can't be sent, so the child checks them
+}
+
+// Keeps track of the isolated cases run by each test. Cases are identified
+// by the test name and the order they're run in. The child process runs the
+// same test function, so it encounters the cases in the same order.
+var isolation = struct {
+	sync.Mutex
+	counts   map[string]int
+	isolated map[string]int           // isolated cases run by each top level test
+	budgets  map[string]time.Duration // time needed to replay each top level test so far
+	skipped  []string                 // cases that didn't complete
+}{
+	counts:   map[string]int{},
+	isolated: map[string]int{},
+	budgets:  map[string]time.Duration{},
+}
+
+// Returns the key identifying the next isolated case in the test along with the
+// time needed to replay all of the earlier isolated cases in the top level test
+func nextIsolatedCase(t *testing.T, oc outputCase) (string, time.Duration) {
+
+	isolation.Lock()
+	defer isolation.Unlock()
+
+	name := t.Name()
+	topLevel := topLevelTestName(name)
+
+	key := name + "#" + strconv.Itoa(isolation.counts[name])
+	replayTime := isolation.budgets[topLevel]
+
+	isolation.counts[name]++
+	isolation.isolated[topLevel]++
+	isolation.budgets[topLevel] += scaledTimeout(oc.timeout)
+
+	return key, replayTime
+}
+
+// Returns the number of isolated cases the test's top level test has run so far
+func isolatedCaseCount(t *testing.T) int {
+
+	isolation.Lock()
+	defer isolation.Unlock()
+
+	return isolation.isolated[topLevelTestName(t.Name())]
+}
+
+// Adds the time a case run in this process took to the time needed
+// to replay the test's top level test in a child process
+func addReplayTime(t *testing.T, elapsed time.Duration) {
+
+	isolation.Lock()
+	defer isolation.Unlock()
+
+	isolation.budgets[topLevelTestName(t.Name())] += elapsed
+}
+
+// Returns the name of the top level test (subtest names are removed)
+func topLevelTestName(testName string) string {
+	return strings.SplitN(testName, "/", 2)[0]
+}
+
+// Builds the -test.run pattern that matches only the specified top level test.
+// All of its subtests are run so earlier cases can be replayed.
+func testRunPattern(testName string) string {
+	return "^" + regexp.QuoteMeta(topLevelTestName(testName)) + "$"
+}
+
+// Runs the output test in a child process and returns any problems found.
+// When called from inside a child process, the case is run in-process instead:
+// the requested case reports its results to the parent and exits, while
+// earlier cases are replayed silently so state (e.g., of a method's object) matches.
+func runIsolatedCase(oc outputCase, randomSeed int64, t *testing.T) []string {
+
+	key, replayTime := nextIsolatedCase(t, oc)
+
+	if target, ok := os.LookupEnv(isolatedCaseEnvVar); ok {
+
+		if key != target {
+
+			if !isSkippedCase(key) {
+				oc.execute(randomSeed)
+			}
+
+			return nil
+		}
+
+		sendIsolatedEvent(isolatedEvent{Started: true})
+
+		result := oc.executeIsolated(randomSeed)
+
+		sendIsolatedEvent(isolatedEvent{
+			Finished:      true,
+			Completed:     result.completed,
+			Panicked:      result.panicked,
+			PanicReport:   result.panicReport,
+			TimedOut:      result.timedOut,
+			Exited:        result.exited,
+			ExitCode:      result.exitCode,
+			ReturnMessage: oc.checkReturns(result),
+		})
+
+		// The parent already has the results, so the exit status doesn't matter
+		// (os.Exit(0) would panic since the child runs with -test.paniconexit0)
+		os.Exit(1)
+	}
+
+	messages, completed := runChildProcess(oc, t, key, replayTime)
+
+	// Keep this process's copy of the object in sync with the child process
+	// so later cases (and the caller) see the state the method left behind
+	if completed && oc.keepsState {
+		oc.execute(randomSeed)
+	}
+
+	if !completed {
+		isolation.Lock()
+		isolation.skipped = append(isolation.skipped, key)
+		isolation.Unlock()
+	}
+
+	return messages
+}
+
+// Returns true if the parent process asked for the case not to be replayed
+func isSkippedCase(key string) bool {
+
+	for _, skipped := range strings.Split(os.Getenv(isolatedSkipEnvVar), "\n") {
+		if skipped == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Runs the case in the child process, sending its stdout (and stderr if it's checked)
+// straight to the parent process
+func (oc outputCase) executeIsolated(randomSeed int64) execution {
+
+	stdinReader, stdinWriter, err := os.Pipe()
+	if err != nil {
+		return execution{panicked: true}
+	}
+
+	oldStdin, oldStdout := os.Stdin, os.Stdout
+	os.Stdin, os.Stdout = stdinReader, isolatedFiles.stdout
+
+	var restoreStderr func()
+	if oc.checksStderr() {
+		restoreStderr = redirectStderr(isolatedFiles.stderr)
+	}
+
+	result := oc.call(randomSeed, func(s string) {
+		stdinWriter.WriteString(s + "\n")
+	})
+
+	os.Stdin, os.Stdout = oldStdin, oldStdout
+
+	if restoreStderr != nil {
+		restoreStderr()
+	}
+
+	return result
+}
+
+// Sends an event to the parent process.
+// If the event can't be sent, the child process tells the parent why and exits
+// (otherwise the parent would blame the function for ending the program).
+func sendIsolatedEvent(event isolatedEvent) {
+
+	if err := json.NewEncoder(isolatedFiles.result).Encode(event); err != nil {
+		os.Stderr.WriteString(isolatedSendError + err.Error() + "\n")
+		os.Exit(1)
+	}
+}
+
+// Re-executes the test binary so only the specified case is run in the child process.
+// Returns the problems found and whether the function completed normally.
+func runChildProcess(oc outputCase, t *testing.T, key string, replayTime time.Duration) ([]string, bool) {
+
+	executable, err := os.Executable()
+	if err != nil {
+		executable = os.Args[0]
+	}
+
+	var pipes [3]struct{ reader, writer *os.File }
+
+	for i := range pipes {
+
+		pipes[i].reader, pipes[i].writer, err = os.Pipe()
+		if err != nil {
+			return []string{"Unable to start isolated test process: " + err.Error()}, false
+		}
+
+		defer pipes[i].reader.Close()
+	}
+
+	isolation.Lock()
+	skipped := strings.Join(isolation.skipped, "\n")
+	isolation.Unlock()
+
+	var output bytes.Buffer
+
+	// -test.paniconexit0 makes os.Exit(0) panic in the child like it does under
+	// go test, so replayed cases that call it are handled the same way as in this process
+	cmd := exec.Command(executable, "-test.run="+testRunPattern(t.Name()), "-test.paniconexit0")
+	cmd.Env = append(os.Environ(), isolatedCaseEnvVar+"="+key, isolatedSkipEnvVar+"="+skipped)
+	cmd.ExtraFiles = []*os.File{pipes[0].writer, pipes[1].writer, pipes[2].writer}
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	cmd.WaitDelay = time.Second
+
+	err = cmd.Start()
+
+	for _, pipe := range pipes {
+		pipe.writer.Close()
+	}
+
+	if err != nil {
+		return []string{"Unable to start isolated test process: " + err.Error()}, false
+	}
+
+	// Collect the case's output until the child process exits
+	var stdout, stderr bytes.Buffer
+	var copying sync.WaitGroup
+
+	copying.Add(2)
+	go func() { io.Copy(&stdout, pipes[1].reader); copying.Done() }()
+	go func() { io.Copy(&stderr, pipes[2].reader); copying.Done() }()
+
+	// Read events from the child until it exits.
+	// Buffered so the reader never blocks once the parent stops listening.
+	events := make(chan isolatedEvent, 2)
+
+	go func() {
+
+		defer close(events)
+
+		decoder := json.NewDecoder(pipes[0].reader)
+
+		for {
+			var event isolatedEvent
+
+			if decoder.Decode(&event) != nil {
+				return
+			}
+
+			events <- event
+		}
+	}()
+
+	started := false
+	deadline := time.After(scaledTimeout(IsolationStartupTimeout) + replayTime)
+
+	for {
+		select {
+
+		case event, ok := <-events:
+
+			if ok && event.Started {
+				started = true
+				deadline = time.After(scaledTimeout(oc.timeout))
+				continue
+			}
+
+			cmd.Wait()
+			copying.Wait()
+
+			result := execution{
+				stdout: outputLines(stdout.String()),
+				stderr: outputLines(stderr.String()),
+			}
+
+			switch {
+
+			case ok:
+				result.completed = event.Completed
+				result.panicked = event.Panicked
+				result.panicReport = event.PanicReport
+				result.timedOut = event.TimedOut
+				result.exited = event.Exited
+				result.exitCode = event.ExitCode
+
+			case strings.Contains(output.String(), isolatedSendError):
+				return []string{"Isolated test process for " + oc.description + " failed: " + isolatedSendProblem(output.String())}, false
+
+			case started && cmd.ProcessState.Exited():
+				// The function ended the program
+				result.exited = true
+				result.exitCode = cmd.ProcessState.ExitCode()
+
+			default:
+				return []string{oc.description + " ended the test process before completing (" + cmd.ProcessState.String() + ")."}, false
+			}
+
+			return oc.checkOutcome(result, event.ReturnMessage), result.completed
+
+		case <-deadline:
+
+			cmd.Process.Kill()
+			cmd.Wait()
+
+			if !started {
+				return []string{"Isolated test process for " + oc.description + " failed to start in time."}, false
+			}
+
+			return []string{oc.timedOutMessage()}, false
+		}
+	}
+}
+
+// Returns the line the child process wrote when it couldn't send its results
+func isolatedSendProblem(output string) string {
+
+	problem := output[strings.Index(output, isolatedSendError):]
+
+	if end := strings.IndexByte(problem, '\n'); end >= 0 {
+		problem = problem[:end]
+	}
+
+	return problem
+}
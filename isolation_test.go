@@ -0,0 +1,144 @@
+package helpers
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Displays a message and ends the program with the exit status
+func exitWithStatus(status int) {
+	fmt.Println("bye")
+	os.Exit(status)
+}
+
+// Returns double the value
+func double(n int) int {
+	fmt.Println("doubling", n)
+	return n * 2
+}
+
+// Returns the value after running the garbage collector
+// (unreferenced *os.File values close their file descriptors when collected)
+func collectGarbage(n int) int {
+	runtime.GC()
+	runtime.GC()
+	return n
+}
+
+// Returns the value after a short delay
+func slowIdentity(n int) int {
+	time.Sleep(300 * time.Millisecond)
+	return n
+}
+
+type isolatedCounter struct {
+	count int
+}
+
+func (c *isolatedCounter) Increment() int {
+	c.count++
+	return c.count
+}
+
+func TestIsolationRoundTrip(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		test    FuncOutputTest
+		problem string
+	}{
+		{
+			name: "expected exit",
+			test: FuncOutputTest{Name: "exitWithStatus", Obj: exitWithStatus, Args: []reflect.Value{reflect.ValueOf(3)},
+				StdoutStrings: []string{"bye"}, ExpectExit: true, ExitCode: 3},
+		},
+		{
+			name: "wrong exit status",
+			test: FuncOutputTest{Name: "exitWithStatus", Obj: exitWithStatus, Args: []reflect.Value{reflect.ValueOf(2)},
+				StdoutStrings: []string{"bye"}, ExpectExit: true, ExitCode: 3},
+			problem: "ended the program with exit status 2, expected exit status 3",
+		},
+		{
+			name: "unexpected exit",
+			test: FuncOutputTest{Name: "exitWithStatus", Obj: exitWithStatus, Args: []reflect.Value{reflect.ValueOf(1)},
+				IgnoreReturns: true, Isolate: true},
+			problem: "ended the program (e.g., by calling os.Exit or log.Fatal) with exit status 1",
+		},
+		{
+			name: "returns checked in the child",
+			test: FuncOutputTest{Name: "double", Obj: double, Args: []reflect.Value{reflect.ValueOf(4)},
+				Returns: []reflect.Value{reflect.ValueOf(8)}, StdoutStrings: []string{"doubling 4"}, Isolate: true},
+		},
+		{
+			name: "wrong return value",
+			test: FuncOutputTest{Name: "double", Obj: double, Args: []reflect.Value{reflect.ValueOf(4)},
+				Returns: []reflect.Value{reflect.ValueOf(9)}, StdoutStrings: []string{"doubling 4"}, Isolate: true},
+			problem: "returned unexpected value",
+		},
+		{
+			name: "garbage collected during the call",
+			test: FuncOutputTest{Name: "collectGarbage", Obj: collectGarbage, Args: []reflect.Value{reflect.ValueOf(5)},
+				Returns: []reflect.Value{reflect.ValueOf(5)}, IgnoreStdout: true, Isolate: true},
+		},
+		{
+			name: "wrong output",
+			test: FuncOutputTest{Name: "double", Obj: double, Args: []reflect.Value{reflect.ValueOf(4)},
+				Returns: []reflect.Value{reflect.ValueOf(8)}, StdoutStrings: []string{"doubling 5"}, Isolate: true},
+			problem: "displayed unexpected output",
+		},
+	}
+
+	for _, test := range tests {
+
+		messages := checkFunctionOutput(test.test, 0, t)
+
+		if test.problem == "" {
+			if len(messages) > 0 {
+				t.Errorf("%s: unexpected failure %v", test.name, messages)
+			}
+		} else if len(messages) == 0 || !strings.Contains(messages[0], test.problem) {
+			t.Errorf("%s: expected failure containing %q, got %v", test.name, test.problem, messages)
+		}
+	}
+}
+
+func TestIsolatedMethodsKeepState(t *testing.T) {
+
+	counter := &isolatedCounter{}
+
+	for i := 1; i <= 3; i++ {
+
+		test := MethodOutputTest{Name: "Increment", Returns: []reflect.Value{reflect.ValueOf(i)}, Isolate: true}
+
+		if messages := checkMethodOutput(counter, test, 0, t); len(messages) > 0 {
+			t.Errorf("call %d: unexpected failure %v", i, messages)
+		}
+	}
+
+	if counter.count != 3 {
+		t.Errorf("count = %d, expected 3", counter.count)
+	}
+}
+
+func TestIsolationReplayTime(t *testing.T) {
+
+	defer func(timeout time.Duration) { IsolationStartupTimeout = timeout }(IsolationStartupTimeout)
+
+	// The child process has to replay the earlier (non-isolated) cases, which take longer than this
+	IsolationStartupTimeout = 500 * time.Millisecond
+
+	tests := []FuncOutputTest{
+		{Name: "slowIdentity", Obj: slowIdentity, Args: []reflect.Value{reflect.ValueOf(1)}, Returns: []reflect.Value{reflect.ValueOf(1)}},
+		{Name: "slowIdentity", Obj: slowIdentity, Args: []reflect.Value{reflect.ValueOf(2)}, Returns: []reflect.Value{reflect.ValueOf(2)}},
+		{Name: "slowIdentity", Obj: slowIdentity, Args: []reflect.Value{reflect.ValueOf(3)}, Returns: []reflect.Value{reflect.ValueOf(3)}},
+		{Name: "double", Obj: double, Args: []reflect.Value{reflect.ValueOf(4)}, Returns: []reflect.Value{reflect.ValueOf(8)},
+			StdoutStrings: []string{"doubling 4"}, Isolate: true},
+	}
+
+	RunFunctionOutputTestsWithMode(tests, 0, ReportAllFailures, t)
+}
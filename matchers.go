@@ -0,0 +1,309 @@
+package helpers
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Matches one or more lines of output.
+// Lines are trimmed of leading/trailing whitespace before being matched.
+type OutputMatcher interface {
+
+	// Number of output lines the matcher consumes
+	Lines() int
+
+	// Returns true if the lines (exactly Lines() of them) match
+	Match(lines []string) bool
+
+	// Describes each expected line (used in failure messages)
+	Expected() []string
+}
+
+// Matcher used for a single line
+type lineMatcher struct {
+	description string
+	match       func(line string) bool
+}
+
+func (m lineMatcher) Lines() int {
+	return 1
+}
+
+func (m lineMatcher) Match(lines []string) bool {
+	return len(lines) == 1 && m.match(lines[0])
+}
+
+func (m lineMatcher) Expected() []string {
+	return []string{m.description}
+}
+
+// Matches a line that is exactly the specified text (the same rule used for StdoutStrings)
+func Exact(text string) OutputMatcher {
+	return lineMatcher{
+		description: text,
+		match: func(line string) bool {
+			return line == text
+		},
+	}
+}
+
+// Matches a line that matches the regular expression.
+// The expression must match the whole line (it's anchored at both ends).
+func Regex(pattern string) OutputMatcher {
+
+	re := regexp.MustCompile(`^(?:` + pattern + `)$`)
+
+	return lineMatcher{
+		description: "/" + pattern + "/",
+		match:       re.MatchString,
+	}
+}
+
+// Matches a line that contains the specified text
+func Contains(text string) OutputMatcher {
+	return lineMatcher{
+		description: "..." + text + "...",
+		match: func(line string) bool {
+			return strings.Contains(line, text)
+		},
+	}
+}
+
+// Matches a line that is the specified text, ignoring character case
+func FoldCase(text string) OutputMatcher {
+	return lineMatcher{
+		description: text + " (any case)",
+		match: func(line string) bool {
+			return strings.EqualFold(line, text)
+		},
+	}
+}
+
+// Matches a line that is the specified text, treating any run of
+// whitespace as a single space
+func NormalizeWhitespace(text string) OutputMatcher {
+
+	normalized := strings.Join(strings.Fields(text), " ")
+
+	return lineMatcher{
+		description: normalized,
+		match: func(line string) bool {
+			return strings.Join(strings.Fields(line), " ") == normalized
+		},
+	}
+}
+
+// Matches numbers (including any sign, decimal point and exponent)
+var numberPattern = regexp.MustCompile(`[-+]?(?:\d+\.?\d*|\.\d+)(?:[eE][-+]?\d+)?`)
+
+// Matches a line that is the specified text, except numbers only need to be
+// within epsilon of the expected numbers (e.g., FloatWithin("Average: 3.33", 0.01)
+// matches "Average: 3.3333")
+func FloatWithin(text string, epsilon float64) OutputMatcher {
+
+	expectedText, expectedNumbers := splitNumbers(text)
+
+	return lineMatcher{
+		description: text + " (numbers ±" + strconv.FormatFloat(epsilon, 'g', -1, 64) + ")",
+		match: func(line string) bool {
+
+			actualText, actualNumbers := splitNumbers(line)
+
+			// A line with the same number of numbers also has the same number of text pieces
+			if len(actualNumbers) != len(expectedNumbers) {
+				return false
+			}
+
+			for i := range expectedText {
+				if actualText[i] != expectedText[i] {
+					return false
+				}
+			}
+
+			for i := range expectedNumbers {
+
+				expected, err := strconv.ParseFloat(expectedNumbers[i], 64)
+				if err != nil {
+					return false
+				}
+
+				actual, err := strconv.ParseFloat(actualNumbers[i], 64)
+				if err != nil || math.Abs(expected-actual) > epsilon {
+					return false
+				}
+			}
+
+			return true
+		},
+	}
+}
+
+// Splits the line into the text around its numbers and the numbers themselves
+// (e.g., "x = 1, y = 2" becomes ["x = ", ", y = ", ""] and ["1", "2"])
+func splitNumbers(line string) ([]string, []string) {
+
+	var text []string
+	var numbers []string
+
+	start := 0
+
+	for _, match := range numberPattern.FindAllStringIndex(line, -1) {
+		text = append(text, line[start:match[0]])
+		numbers = append(numbers, line[match[0]:match[1]])
+		start = match[1]
+	}
+
+	return append(text, line[start:]), numbers
+}
+
+// Matches a set of lines that can appear in any order
+// (e.g., printing the contents of a map)
+type anyOrderMatcher struct {
+	matchers []OutputMatcher
+}
+
+// Matches a group of lines in any order.
+// Each matcher must match exactly one line.
+func AnyOrder(matchers ...OutputMatcher) OutputMatcher {
+
+	for _, m := range matchers {
+		if m.Lines() != 1 {
+			panic("AnyOrder matchers must each match a single line")
+		}
+	}
+
+	return anyOrderMatcher{matchers: matchers}
+}
+
+func (m anyOrderMatcher) Lines() int {
+	return len(m.matchers)
+}
+
+func (m anyOrderMatcher) Match(lines []string) bool {
+
+	if len(lines) != len(m.matchers) {
+		return false
+	}
+
+	// matching[i] = indexes of the lines matcher i matches
+	matching := make([][]int, len(m.matchers))
+
+	for i, matcher := range m.matchers {
+		for j, line := range lines {
+			if matcher.Match([]string{line}) {
+				matching[i] = append(matching[i], j)
+			}
+		}
+
+		if len(matching[i]) == 0 {
+			return false
+		}
+	}
+
+	// Each matcher needs a different line
+	return pairAll(matching, len(lines))
+}
+
+func (m anyOrderMatcher) Expected() []string {
+
+	var expected []string
+
+	for _, matcher := range m.matchers {
+		expected = append(expected, matcher.Expected()...)
+	}
+
+	return expected
+}
+
+// Returns true if any of the matchers matches the line (used to line up diffs)
+func (m anyOrderMatcher) matchesLine(line string) bool {
+
+	for _, matcher := range m.matchers {
+		if matcher.Match([]string{line}) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Converts expected strings to Exact matchers
+func exactMatchers(lines []string) []OutputMatcher {
+
+	matchers := make([]OutputMatcher, len(lines))
+
+	for i, line := range lines {
+		matchers[i] = Exact(line)
+	}
+
+	return matchers
+}
+
+// Checks output lines against a list of matchers
+type outputComparison struct {
+	matchers []OutputMatcher
+	expected []string // description of each expected line
+	owners   []int    // index of the matcher each expected line belongs to
+	actual   []string // actual lines (untrimmed)
+}
+
+func newOutputComparison(matchers []OutputMatcher, actual []string) outputComparison {
+
+	cmp := outputComparison{matchers: matchers, actual: actual}
+
+	for i, m := range matchers {
+
+		descriptions := m.Expected()
+
+		// Keep one description per line so the diff lines up
+		for len(descriptions) < m.Lines() {
+			descriptions = append(descriptions, "")
+		}
+
+		cmp.expected = append(cmp.expected, descriptions[:m.Lines()]...)
+
+		for j := 0; j < m.Lines(); j++ {
+			cmp.owners = append(cmp.owners, i)
+		}
+	}
+
+	return cmp
+}
+
+// Returns the 0 based index of the first expected line that doesn't match
+// (-1 if all of the lines match). Only valid if the line counts are the same.
+func (cmp outputComparison) firstMismatch() int {
+
+	line := 0
+
+	for _, m := range cmp.matchers {
+
+		var trimmed []string
+		for _, s := range cmp.actual[line : line+m.Lines()] {
+			trimmed = append(trimmed, strings.TrimSpace(s))
+		}
+
+		if !m.Match(trimmed) {
+			return line
+		}
+
+		line += m.Lines()
+	}
+
+	return -1
+}
+
+// Returns true if expected line i matches actual line j (used to line up diffs)
+func (cmp outputComparison) lineEqual(i int, j int) bool {
+
+	m := cmp.matchers[cmp.owners[i]]
+	line := strings.TrimSpace(cmp.actual[j])
+
+	if group, ok := m.(anyOrderMatcher); ok {
+		return group.matchesLine(line)
+	}
+
+	return m.Lines() == 1 && m.Match([]string{line})
+}
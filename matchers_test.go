@@ -0,0 +1,110 @@
+package helpers
+
+import (
+	"testing"
+)
+
+func TestOutputMatchers(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		matcher OutputMatcher
+		lines   []string
+		match   bool
+	}{
+		{"exact", Exact("Total: 5"), []string{"Total: 5"}, true},
+		{"exact mismatch", Exact("Total: 5"), []string{"Total: 50"}, false},
+		{"exact too many lines", Exact("a"), []string{"a", "a"}, false},
+		{"regex", Regex(`Total: \d+`), []string{"Total: 50"}, true},
+		{"regex is anchored", Regex(`Total: \d`), []string{"Total: 50"}, false},
+		{"regex alternatives are anchored", Regex(`a|b`), []string{"ab"}, false},
+		{"contains", Contains("error"), []string{"An error occurred"}, true},
+		{"contains mismatch", Contains("error"), []string{"All good"}, false},
+		{"fold case", FoldCase("Hello"), []string{"HELLO"}, true},
+		{"fold case mismatch", FoldCase("Hello"), []string{"Hi"}, false},
+		{"normalize whitespace", NormalizeWhitespace("a  b\tc"), []string{"a b   c"}, true},
+		{"normalize whitespace mismatch", NormalizeWhitespace("a b"), []string{"ab"}, false},
+		{"float within", FloatWithin("Average: 3.33", 0.01), []string{"Average: 3.3333"}, true},
+		{"float within several numbers", FloatWithin("x = 1.5, y = -2", 0.1), []string{"x = 1.45, y = -2.05"}, true},
+		{"float within exponent", FloatWithin("1e3", 0.5), []string{"1000.2"}, true},
+		{"float outside epsilon", FloatWithin("Average: 3.33", 0.01), []string{"Average: 3.5"}, false},
+		{"float text mismatch", FloatWithin("Average: 3.33", 0.01), []string{"Mean: 3.33"}, false},
+		{"float missing number", FloatWithin("Total: 5", 0.1), []string{"Total: #"}, false},
+		{"float extra number", FloatWithin("Total: 5", 0.1), []string{"Total: 5 5"}, false},
+		{"float number where text belongs", FloatWithin("Total: #", 0.1), []string{"Total: 5"}, false},
+		{"float no numbers", FloatWithin("Total: #", 0.1), []string{"Total: #"}, true},
+		{"any order", AnyOrder(Exact("a"), Exact("b")), []string{"b", "a"}, true},
+		{"any order backtracks", AnyOrder(Contains("a"), Exact("ab")), []string{"ab", "a"}, true},
+		{"any order duplicate line", AnyOrder(Exact("a"), Exact("b")), []string{"a", "a"}, false},
+		{"any order wrong count", AnyOrder(Exact("a"), Exact("b")), []string{"a"}, false},
+	}
+
+	for _, test := range tests {
+		if match := test.matcher.Match(test.lines); match != test.match {
+			t.Errorf("%s: Match(%q) = %v, expected %v", test.name, test.lines, match, test.match)
+		}
+	}
+}
+
+func TestAnyOrderManyLines(t *testing.T) {
+
+	// Many identical matchers and one line that doesn't match
+	// (trying every assignment of lines to matchers would never finish)
+	var matchers []OutputMatcher
+	var lines []string
+
+	for i := 0; i < 200; i++ {
+		matchers = append(matchers, Exact("same"))
+		lines = append(lines, "same")
+	}
+
+	if !AnyOrder(matchers...).Match(lines) {
+		t.Error("expected identical lines to match")
+	}
+
+	matchers[0] = Contains("s")
+	lines[199] = "different"
+
+	if AnyOrder(matchers...).Match(lines) {
+		t.Error("expected a line that no matcher accepts to fail the match")
+	}
+
+	lines[199] = "sat"
+
+	if !AnyOrder(matchers...).Match(lines) {
+		t.Error("expected the only matcher accepting the last line to be paired with it")
+	}
+}
+
+func TestAnyOrderRequiresSingleLineMatchers(t *testing.T) {
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for a multiple line matcher")
+		}
+	}()
+
+	AnyOrder(AnyOrder(Exact("a"), Exact("b")))
+}
+
+func TestOutputComparisonFirstMismatch(t *testing.T) {
+
+	matchers := []OutputMatcher{Exact("first"), AnyOrder(Exact("a"), Exact("b")), FloatWithin("Total: 5", 0.1)}
+
+	tests := []struct {
+		actual   []string
+		mismatch int
+	}{
+		{[]string{"first", "b", "a", "Total: 5.05"}, -1},
+		{[]string{"  first  ", "a", "b", "Total: 5"}, -1},
+		{[]string{"second", "a", "b", "Total: 5"}, 0},
+		{[]string{"first", "a", "c", "Total: 5"}, 1},
+		{[]string{"first", "a", "b", "Total: #"}, 3},
+	}
+
+	for _, test := range tests {
+		if mismatch := newOutputComparison(matchers, test.actual).firstMismatch(); mismatch != test.mismatch {
+			t.Errorf("firstMismatch(%q) = %d, expected %d", test.actual, mismatch, test.mismatch)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package helpers
+
+import (
+	"testing"
+	"time"
+)
+
+// Controls how the runners report problems once a test has failed
+type FailureMode int
+
+// FailureMode enum values
+const (
+	// Once the test has failed, problems found by later cases aren't reported
+	// (they're still scored). This is the original behavior.
+	StopAfterFailure FailureMode = iota
+
+	// Every case runs as its own subtest (t.Run) and reports all of its
+	// problems, so each case passes or fails independently in "go test -v" output
+	ReportAllFailures
+)
+
+// Failure mode used by the runners that don't take a FailureMode argument
+var DefaultFailureMode = StopAfterFailure
+
+// Runs a single test case using the failure mode and records its result.
+// check runs the case and returns its score entry.
+// report = true if problems should be reported when using StopAfterFailure.
+// Returns true if the case passed.
+func runCase(t *testing.T, mode FailureMode, name string, report bool, check func(t *testing.T) ScoreEntry) bool {
+
+	if mode == ReportAllFailures {
+
+		passed := false
+
+		t.Run(name, func(t *testing.T) {
+			entry := timedCheck(t, check)
+			recordResult(t, entry, true)
+			passed = entry.Passed
+		})
+
+		return passed
+	}
+
+	entry := timedCheck(t, check)
+	recordResult(t, entry, report)
+
+	return entry.Passed
+}
+
+// Runs the check and records how long it took.
+// Checks run in this process are replayed by the isolated child processes started
+// later in the same top level test, so their time is added to the replay time
+// (isolated cases add their timeout instead, see nextIsolatedCase).
+func timedCheck(t *testing.T, check func(t *testing.T) ScoreEntry) ScoreEntry {
+
+	start := time.Now()
+	isolated := isolatedCaseCount(t)
+
+	entry := check(t)
+	entry.Duration = time.Since(start)
+
+	if isolatedCaseCount(t) == isolated {
+		addReplayTime(t, entry.Duration)
+	}
+
+	return entry
+}
@@ -0,0 +1,261 @@
+package helpers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Whole program testing struct.
+// The program is run the way a user would run it (e.g., "./program Args...").
+// Env = extra environment variables (e.g., "DEBUG=1")
+// Dir = working directory the program is run in ("" = a new empty temporary directory)
+// ExitCode = exit status the program should end with (usually 0)
+// StdoutMatchers/StderrMatchers = used instead of StdoutStrings/StderrStrings when set
+// Stderr is only checked when StderrStrings or StderrMatchers is set (or CheckStderr is true)
+// and IgnoreStderr is false.
+// Files = expected lines of each file the program should create (file names are relative to the working directory)
+// FileMatchers = used instead of Files for the file names they include
+// Timeout = amount of time the program is given to complete (0 = DefaultTimeout)
+// Points/Category = weight and category used for scoring (see Scorecard; 0 Points = DefaultPoints)
+// Ungraded = true if the check is reported but worth no points (Points is ignored)
+type ProgramTest struct {
+	Name           string
+	Args           []string
+	Env            []string
+	StdinStrings   []string
+	Dir            string
+	IgnoreStdout   bool
+	StdoutStrings  []string
+	StdoutMatchers []OutputMatcher
+	IgnoreStderr   bool
+	StderrStrings  []string
+	StderrMatchers []OutputMatcher
+	ExitCode       int
+	Files          map[string][]string
+	FileMatchers   map[string][]OutputMatcher
+	HideExpected   bool
+	Timeout        time.Duration
+	Points         float64
+	Ungraded       bool
+	Category       string
+}
+
+// Runs whole program tests.
+// The main package at packagePath (e.g., "./cmd/app" or a full import path) is built once
+// using the go command and the resulting program is run for each test.
+func RunProgramTests(packagePath string, tests []ProgramTest, t *testing.T) {
+	RunProgramTestsWithMode(packagePath, tests, DefaultFailureMode, t)
+}
+
+// Runs whole program tests using the specified failure mode
+func RunProgramTestsWithMode(packagePath string, tests []ProgramTest, mode FailureMode, t *testing.T) {
+
+	program, buildErr := buildProgram(packagePath, t.TempDir())
+
+	for _, test := range tests {
+
+		// If a test failure has already occurred, no need to report further problems.
+		// The tests are still run so they can be scored.
+		runCase(t, mode, test.Name, !t.Failed(), func(t *testing.T) ScoreEntry {
+
+			// A program that doesn't build fails every test
+			if buildErr != nil {
+				return outputScoreEntry(test.Name, test.Category, test.Points, test.Ungraded, test.HideExpected, []string{buildErr.Error()})
+			}
+
+			return outputScoreEntry(test.Name, test.Category, test.Points, test.Ungraded, test.HideExpected, checkProgram(program, test, t))
+		})
+	}
+}
+
+// Builds the main package into the directory.
+// Returns the path of the program (or the compiler's output if it didn't build).
+func buildProgram(packagePath string, dir string) (string, error) {
+
+	program := filepath.Join(dir, "program")
+	if runtime.GOOS == "windows" {
+		program += ".exe"
+	}
+
+	goCommand, err := exec.LookPath("go")
+	if err != nil {
+		return "", errors.New("Unable to build the program: the go command could not be found.")
+	}
+
+	output, err := exec.Command(goCommand, "build", "-o", program, packagePath).CombinedOutput()
+	if err != nil {
+		return "", errors.New("Unable to build the program. Compiler output:\n" + strings.TrimSpace(string(output)))
+	}
+
+	return program, nil
+}
+
+// Result of running a program
+type programRun struct {
+	stdout   []string
+	stderr   []string
+	exitCode int
+	state    *os.ProcessState
+	timedOut bool
+}
+
+// Runs the program test.
+// Returns an error message for each problem found.
+func checkProgram(program string, test ProgramTest, t *testing.T) []string {
+
+	dir := test.Dir
+	if dir == "" {
+		dir = t.TempDir()
+	}
+
+	oc := outputCase{
+		description:    "Program",
+		ignoreStdout:   test.IgnoreStdout,
+		stdoutStrings:  test.StdoutStrings,
+		stdoutMatchers: test.StdoutMatchers,
+		ignoreStderr:   test.IgnoreStderr,
+		stderrStrings:  test.StderrStrings,
+		stderrMatchers: test.StderrMatchers,
+		hideExpected:   test.HideExpected,
+		timeout:        test.Timeout,
+	}
+
+	result, err := runProgram(program, test, dir)
+	if err != nil {
+		return []string{"Unable to run the program: " + err.Error()}
+	}
+
+	if result.timedOut {
+		return []string{oc.timedOutMessage()}
+	}
+
+	if message := checkProgramExit(result, test.ExitCode); message != "" {
+		return []string{message}
+	}
+
+	if !oc.ignoreStdout {
+
+		if message := oc.checkStdout(result.stdout); message != "" {
+			return []string{message}
+		}
+	}
+
+	if oc.checksStderr() {
+
+		if message := oc.checkStderr(result.stderr); message != "" {
+			return []string{message}
+		}
+	}
+
+	if message := checkProgramFiles(oc, test, dir); message != "" {
+		return []string{message}
+	}
+
+	return nil
+}
+
+// Runs the program in the directory using the test's arguments, environment and input
+func runProgram(program string, test ProgramTest, dir string) (programRun, error) {
+
+	ctx, cancel := context.WithTimeout(context.Background(), scaledTimeout(test.Timeout))
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, program, test.Args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), test.Env...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.WaitDelay = time.Second
+
+	if len(test.StdinStrings) > 0 {
+		cmd.Stdin = strings.NewReader(strings.Join(test.StdinStrings, "\n") + "\n")
+	}
+
+	err := cmd.Run()
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return programRun{timedOut: true}, nil
+	}
+
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		return programRun{}, err
+	}
+
+	return programRun{
+		stdout:   outputLines(stdout.String()),
+		stderr:   outputLines(stderr.String()),
+		exitCode: cmd.ProcessState.ExitCode(),
+		state:    cmd.ProcessState,
+	}, nil
+}
+
+// Checks the program's exit status.
+// Returns an error message if it isn't the expected one ("" if it is).
+func checkProgramExit(result programRun, exitCode int) string {
+
+	if result.exitCode == exitCode {
+		return ""
+	}
+
+	// Killed by a signal
+	if !result.state.Exited() {
+		return "Program ended before completing (" + result.state.String() + ")."
+	}
+
+	// Unrecovered panics end Go programs with exit status 2
+	if result.exitCode == 2 && len(result.stderr) > 0 && strings.HasPrefix(result.stderr[0], "panic: ") {
+		return runtimeErrorMessage
+	}
+
+	return "Program ended with exit status " + strconv.Itoa(result.exitCode) + ", expected exit status " + strconv.Itoa(exitCode) + "."
+}
+
+// Checks the files the program should have created.
+// Returns an error message for the first file that doesn't match ("" if they all match).
+func checkProgramFiles(oc outputCase, test ProgramTest, dir string) string {
+
+	var names []string
+
+	for name := range test.Files {
+		names = append(names, name)
+	}
+
+	for name := range test.FileMatchers {
+		if _, ok := test.Files[name]; !ok {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return "Program did not create the file '" + name + "'."
+		}
+
+		message := oc.checkLines(test.FileMatchers[name], test.Files[name], outputLines(string(data)),
+			" wrote unexpected number of lines to the file '"+name+"'.",
+			" wrote unexpected contents to the file '"+name+"'.")
+
+		if message != "" {
+			return message
+		}
+	}
+
+	return ""
+}
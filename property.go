@@ -0,0 +1,341 @@
+package helpers
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Number of inputs generated for a property test that doesn't specify Cases
+var DefaultPropertyCases = 100
+
+// Maximum number of extra calls made while shrinking a failing input
+var MaxShrinkCalls = 500
+
+// Property testing struct.
+// The function is called with generated inputs: edge cases first (e.g., 0, -1, the
+// max int, "", nil/empty slices and maps), then random values that grow in size.
+// Each result is checked against either:
+//
+//	Reference = a reference implementation with the same signature (returns and stdout must match)
+//	Property  = a function taking the arguments followed by the returns and returning
+//	            true if they're acceptable (e.g., func(nums []int, max int) bool)
+//
+// When an input fails, it's shrunk to the smallest failing input that can be found
+// and that input is shown in the failure message.
+// Inputs the Reference can't handle (it panics or times out) are skipped.
+// Generators = generator for each parameter (nil entries use RandomValues). Each generator
+// must create values of the parameter's type (e.g., IntRange only works for int parameters).
+// Seed = seed used to generate inputs (the same seed always generates the same inputs)
+// Cases = number of inputs to try (0 = DefaultPropertyCases)
+// Timeout = amount of time the function is given for each input (0 = DefaultTimeout)
+// ReturnComparators = comparator for each return position when using a Reference
+// IgnoreStdout = true to only compare the Reference's returns
+// Points/Category = weight and category used for scoring (see Scorecard; 0 Points = DefaultPoints)
+// Ungraded = true if the check is reported but worth no points (Points is ignored)
+type PropertyTest struct {
+	Name              string
+	Obj               interface{}
+	Reference         interface{}
+	Property          interface{}
+	Generators        []Generator
+	Seed              int64
+	Cases             int
+	Timeout           time.Duration
+	ReturnComparators []Comparator
+	IgnoreStdout      bool
+	Points            float64
+	Ungraded          bool
+	Category          string
+}
+
+// Runs property tests on functions using generated inputs
+func RunPropertyTests(tests []PropertyTest, randomSeed int64, t *testing.T) {
+	RunPropertyTestsWithMode(tests, randomSeed, DefaultFailureMode, t)
+}
+
+// Runs property tests using the specified failure mode
+func RunPropertyTestsWithMode(tests []PropertyTest, randomSeed int64, mode FailureMode, t *testing.T) {
+
+	for _, test := range tests {
+
+		if test.Reference == nil && test.Property == nil {
+			t.Fatal("Property test for '" + test.Name + "' needs a Reference or a Property")
+		}
+
+		if property := reflect.TypeOf(test.Property); property != nil &&
+			(property.Kind() != reflect.Func || property.NumOut() != 1 || property.Out(0).Kind() != reflect.Bool) {
+			t.Fatal("Property for '" + test.Name + "' must be a function that returns a bool")
+		}
+
+		if reference := reflect.TypeOf(test.Reference); reference != nil && reference.Kind() != reflect.Func {
+			t.Fatal("Reference for '" + test.Name + "' is not a function")
+		}
+
+		// If a test failure has already occurred, no need to report further problems.
+		// The tests are still run so they can be scored.
+		runCase(t, mode, test.Name, !t.Failed(), func(t *testing.T) ScoreEntry {
+
+			messages, err := checkProperty(test, randomSeed)
+			if err != nil {
+				t.Fatal("Invalid test case. " + err.Error())
+			}
+
+			return newScoreEntry(test.Name, test.Category, "Property", test.Points, test.Ungraded, messages)
+		})
+	}
+}
+
+// Runs the property test.
+// Returns an error message for each problem found, or an error if one of the
+// Generators creates a value that can't be passed to the function (the test author's mistake).
+func checkProperty(test PropertyTest, randomSeed int64) ([]string, error) {
+
+	expected := reflect.TypeOf(test.Reference)
+	if test.Reference == nil {
+		expected = reflect.TypeOf(test.Property)
+	}
+
+	if messages := checkFunctionAnatomy(propertyAnatomyTest(test, expected)); len(messages) > 0 {
+		return messages, nil
+	}
+
+	p := propertyRun{test: test, function: reflect.ValueOf(test.Obj), randomSeed: randomSeed}
+	functionType := p.function.Type()
+
+	cases := test.Cases
+	if cases == 0 {
+		cases = DefaultPropertyCases
+	}
+
+	var edges [][]reflect.Value
+	edgeCount := 0
+
+	for i := 0; i < functionType.NumIn(); i++ {
+
+		edges = append(edges, edgeValues(functionType.In(i), 0))
+
+		if len(edges[i]) > edgeCount {
+			edgeCount = len(edges[i])
+		}
+	}
+
+	r := rand.New(rand.NewSource(test.Seed))
+
+	for n := 0; n < cases; n++ {
+
+		args := make([]reflect.Value, functionType.NumIn())
+
+		for i := range args {
+
+			switch {
+			case i < len(test.Generators) && test.Generators[i] != nil:
+
+				args[i] = test.Generators[i](r, 1+n*maxGeneratedSize/cases)
+
+				// Checked here so the call doesn't panic and get reported as the function's runtime error
+				if !args[i].IsValid() || !args[i].Type().AssignableTo(functionType.In(i)) {
+					return nil, errors.New("Generator " + strconv.Itoa(i+1) + " for '" + test.Name + "' created " +
+						generatedType(args[i]) + ", expected a value of type " + functionType.In(i).String())
+				}
+
+			case n < edgeCount:
+				args[i] = edges[i][n%len(edges[i])]
+			default:
+				args[i] = randomValue(functionType.In(i), r, 1+n*maxGeneratedSize/cases, 0)
+			}
+		}
+
+		messages, skipped := p.try(args)
+		if skipped || len(messages) == 0 {
+			continue
+		}
+
+		args, messages, shrinks := p.shrink(args, messages)
+
+		header := "Function '" + test.Name + "' failed a property check after " + strconv.Itoa(n+1) + " generated input(s)."
+		if shrinks > 0 {
+			header += " The failing input was simplified " + strconv.Itoa(shrinks) + " time(s)."
+		}
+
+		header += "\nFailing input: " + formatArguments(args, functionType.IsVariadic())
+
+		return []string{header + "\n" + strings.Join(messages, "\n")}, nil
+	}
+
+	return nil, nil
+}
+
+// Describes the type of a generated value
+func generatedType(v reflect.Value) string {
+
+	if !v.IsValid() {
+		return "an invalid value (e.g., reflect.Value{})"
+	}
+
+	return "a value of type " + v.Type().String()
+}
+
+// Builds the anatomy test for the function from the expected signature
+// (the Reference's type or the Property's parameters)
+func propertyAnatomyTest(test PropertyTest, expected reflect.Type) FuncAnatomyTest {
+
+	anatomy := FuncAnatomyTest{Name: test.Name, Obj: test.Obj}
+
+	if expected == nil || expected.Kind() != reflect.Func {
+		return anatomy
+	}
+
+	// The Property's parameters are the function's parameters followed by its returns
+	params := expected.NumIn()
+	if test.Reference == nil {
+		if function := reflect.TypeOf(test.Obj); function != nil && function.Kind() == reflect.Func && function.NumIn() < params {
+			params = function.NumIn()
+		}
+	}
+
+	for i := 0; i < expected.NumIn(); i++ {
+		if i < params {
+			anatomy.ArgTypes = append(anatomy.ArgTypes, expected.In(i))
+		} else {
+			anatomy.ReturnTypes = append(anatomy.ReturnTypes, expected.In(i))
+		}
+	}
+
+	if test.Reference != nil {
+		anatomy.Variadic = expected.IsVariadic()
+		for i := 0; i < expected.NumOut(); i++ {
+			anatomy.ReturnTypes = append(anatomy.ReturnTypes, expected.Out(i))
+		}
+	} else if function := reflect.TypeOf(test.Obj); function != nil && function.Kind() == reflect.Func {
+		anatomy.Variadic = function.IsVariadic()
+	}
+
+	return anatomy
+}
+
+// State used while running a property test
+type propertyRun struct {
+	test       PropertyTest
+	function   reflect.Value
+	randomSeed int64
+	calls      int  // number of times the function has been called
+	timedOut   bool // true if the last call timed out
+}
+
+// Calls the function with the arguments and checks the result.
+// Returns the problems found and whether the input was skipped.
+func (p *propertyRun) try(args []reflect.Value) ([]string, bool) {
+
+	p.calls++
+	p.timedOut = false
+
+	oc := outputCase{
+		description:  "Function '" + p.test.Name + "'",
+		function:     p.function,
+		args:         copyValues(args),
+		variadic:     p.function.Type().IsVariadic(),
+		ignoreStdout: true,
+		timeout:      p.test.Timeout,
+	}
+
+	if p.test.Reference != nil {
+
+		reference := referenceCase("Reference function for '"+p.test.Name+"'", reflect.ValueOf(p.test.Reference), copyValues(args), oc.variadic, nil, p.test.Timeout).execute(p.randomSeed)
+
+		if reference.timedOut || reference.panicked {
+			return nil, true
+		}
+
+		oc.returns, oc.stdoutStrings = referenceExpectations(nil, nil, nil, reference)
+		oc.comparators = p.test.ReturnComparators
+		oc.ignoreStdout = p.test.IgnoreStdout
+
+		result := oc.execute(p.randomSeed)
+		p.timedOut = result.timedOut
+
+		return oc.check(result), false
+	}
+
+	oc.ignoreReturns = true
+
+	result := oc.execute(p.randomSeed)
+	p.timedOut = result.timedOut
+
+	if messages := oc.check(result); len(messages) > 0 {
+		return messages, false
+	}
+
+	if !p.propertyHolds(args, result.returnVals) {
+		return []string{oc.description + " returned values that don't satisfy the property check.\nReturned: " + formatArguments(result.returnVals, false)}, false
+	}
+
+	return nil, false
+}
+
+// Calls the Property function with the arguments followed by the returns
+func (p *propertyRun) propertyHolds(args []reflect.Value, returns []reflect.Value) (holds bool) {
+
+	defer func() {
+		if recover() != nil {
+			holds = false
+		}
+	}()
+
+	return reflect.ValueOf(p.test.Property).Call(append(copyValues(args), returns...))[0].Bool()
+}
+
+// Repeatedly replaces an argument with a simpler version as long as the input keeps failing.
+// Returns the simplest failing input found, its problems and the number of times it was simplified.
+func (p *propertyRun) shrink(args []reflect.Value, messages []string) ([]reflect.Value, []string, int) {
+
+	// Inputs that time out aren't shrunk since every attempt could take the whole timeout
+	if p.timedOut {
+		return args, messages, 0
+	}
+
+	shrinks := 0
+	limit := p.calls + MaxShrinkCalls
+
+	for simplified := true; simplified && p.calls < limit; {
+
+		simplified = false
+
+		for i := 0; i < len(args) && !simplified; i++ {
+			for _, candidate := range shrinkCandidates(args[i]) {
+
+				if p.calls >= limit {
+					break
+				}
+
+				trial := append([]reflect.Value{}, args...)
+				trial[i] = candidate
+
+				if trialMessages, skipped := p.try(trial); !skipped && !p.timedOut && len(trialMessages) > 0 {
+					args, messages = trial, trialMessages
+					shrinks++
+					simplified = true
+					break
+				}
+			}
+		}
+	}
+
+	return args, messages, shrinks
+}
+
+// Returns deep copies of the values
+func copyValues(values []reflect.Value) []reflect.Value {
+
+	copies := make([]reflect.Value, len(values))
+
+	for i, v := range values {
+		copies[i] = copyValue(v)
+	}
+
+	return copies
+}
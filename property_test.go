@@ -0,0 +1,190 @@
+package helpers
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Returns the value, except for values of 10 or more (which are off by one)
+func offByOneFromTen(n int) int {
+
+	if n >= 10 {
+		return n + 1
+	}
+
+	return n
+}
+
+// Returns the value
+func identity(n int) int {
+	return n
+}
+
+// Returns the sum of the numbers, except that the third and later numbers are skipped
+func sumOfFirstTwo(nums []int) int {
+
+	sum := 0
+
+	for i, n := range nums {
+		if i < 2 {
+			sum += n
+		}
+	}
+
+	return sum
+}
+
+// Returns the value as an int64
+func widen(n int64) int64 {
+	return n
+}
+
+func TestEdgeValues(t *testing.T) {
+
+	tests := []struct {
+		t        reflect.Type
+		expected []interface{}
+	}{
+		{reflect.TypeOf(false), []interface{}{false, true}},
+		{reflect.TypeOf(int8(0)), []interface{}{int8(0), int8(1), int8(-1), int8(127), int8(-128)}},
+		{reflect.TypeOf(uint16(0)), []interface{}{uint16(0), uint16(1), uint16(65535)}},
+		{reflect.TypeOf(complex64(0)), []interface{}{complex64(0), complex64(1), complex64(1i), complex64(-1 - 1i)}},
+		{reflect.TypeOf([]bool{}), []interface{}{[]bool(nil), []bool{}, []bool{false}, []bool{true}}},
+		{reflect.TypeOf((*bool)(nil)), []interface{}{(*bool)(nil), new(bool), func() *bool { b := true; return &b }()}},
+		{reflect.TypeOf([2]bool{}), []interface{}{[2]bool{false, false}, [2]bool{true, true}}},
+	}
+
+	for _, test := range tests {
+
+		var actual []interface{}
+		for _, value := range edgeValues(test.t, 0) {
+			actual = append(actual, value.Interface())
+		}
+
+		if !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("edgeValues(%s) = %#v, expected %#v", test.t, actual, test.expected)
+		}
+	}
+
+	texts := edgeValues(reflect.TypeOf(""), 0)
+	if texts[0].String() != "" || len(texts) < 5 {
+		t.Errorf("expected the empty string and non-ASCII edge cases, got %v", texts)
+	}
+}
+
+func TestRandomValuesAreDeterministic(t *testing.T) {
+
+	generate := func(seed int64) []interface{} {
+
+		r := rand.New(rand.NewSource(seed))
+		generator := RandomValues(reflect.TypeOf(map[string][]int{}))
+
+		var values []interface{}
+		for size := 1; size <= maxGeneratedSize; size++ {
+			values = append(values, generator(r, size).Interface())
+		}
+
+		return values
+	}
+
+	if !reflect.DeepEqual(generate(7), generate(7)) {
+		t.Error("expected the same seed to generate the same values")
+	}
+
+	if reflect.DeepEqual(generate(7), generate(8)) {
+		t.Error("expected different seeds to generate different values")
+	}
+}
+
+func TestCheckProperty(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		test    PropertyTest
+		problem string // "" = the property test should pass
+	}{
+		{
+			name: "matches the reference",
+			test: PropertyTest{Name: "identity", Obj: identity, Reference: identity, Seed: 1},
+		},
+		{
+			name:    "shrinks to the smallest failing int",
+			test:    PropertyTest{Name: "offByOneFromTen", Obj: offByOneFromTen, Reference: identity, Seed: 1},
+			problem: "\nFailing input: (10)\n",
+		},
+		{
+			name:    "shrinks to the shortest failing slice",
+			test:    PropertyTest{Name: "sumOfFirstTwo", Obj: sumOfFirstTwo, Reference: sumOfSlice, Seed: 1},
+			problem: "\nFailing input: ([]int{0, 0, 1})\n",
+		},
+		{
+			name: "property holds",
+			test: PropertyTest{Name: "identity", Obj: identity, Seed: 1,
+				Property: func(n int, result int) bool { return n == result }},
+		},
+		{
+			name: "property fails",
+			test: PropertyTest{Name: "offByOneFromTen", Obj: offByOneFromTen, Seed: 1,
+				Property: func(n int, result int) bool { return n == result }},
+			problem: "\nFailing input: (10)\n",
+		},
+		{
+			name: "generator matching the parameter",
+			test: PropertyTest{Name: "widen", Obj: widen, Reference: widen, Seed: 1,
+				Generators: []Generator{OneOf(reflect.ValueOf(int64(1)), reflect.ValueOf(int64(-5)))}},
+		},
+	}
+
+	for _, test := range tests {
+
+		messages, err := checkProperty(test.test, 0)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", test.name, err)
+			continue
+		}
+
+		if test.problem == "" {
+			if len(messages) > 0 {
+				t.Errorf("%s: unexpected failure %v", test.name, messages)
+			}
+		} else if len(messages) == 0 || !strings.Contains(messages[0], test.problem) {
+			t.Errorf("%s: expected failure containing %q, got %v", test.name, test.problem, messages)
+		}
+	}
+}
+
+func TestCheckPropertySameSeedSameResult(t *testing.T) {
+
+	test := PropertyTest{Name: "offByOneFromTen", Obj: offByOneFromTen, Reference: identity, Seed: 42, Cases: 50}
+
+	first, _ := checkProperty(test, 0)
+	second, _ := checkProperty(test, 0)
+
+	if len(first) == 0 || !reflect.DeepEqual(first, second) {
+		t.Errorf("expected the same failure both times, got %v and %v", first, second)
+	}
+}
+
+func TestCheckPropertyGeneratorTypes(t *testing.T) {
+
+	tests := []struct {
+		name      string
+		generator Generator
+		expected  string
+	}{
+		{name: "IntRange", generator: IntRange(1, 5), expected: "Generator 1 for 'widen' created a value of type int, expected a value of type int64"},
+		{name: "OneOf", generator: OneOf(reflect.ValueOf("a")), expected: "Generator 1 for 'widen' created a value of type string, expected a value of type int64"},
+		{name: "invalid value", generator: OneOf(reflect.Value{}), expected: "Generator 1 for 'widen' created an invalid value"},
+	}
+
+	for _, test := range tests {
+
+		messages, err := checkProperty(PropertyTest{Name: "widen", Obj: widen, Reference: widen, Generators: []Generator{test.generator}}, 0)
+
+		if err == nil || !strings.HasPrefix(err.Error(), test.expected) {
+			t.Errorf("%s: expected an error starting with %q, got %v (messages %v)", test.name, test.expected, err, messages)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package helpers
+
+import "reflect"
+
+// Kind of receiver a method must be declared with
+type ReceiverKind int
+
+// ReceiverKind enum values
+const (
+	AnyReceiver     ReceiverKind = iota // either kind
+	ValueReceiver                       // e.g., func (a Account) Balance() float64
+	PointerReceiver                     // e.g., func (a *Account) Deposit(amount float64)
+)
+
+// Returns a pointer to the object being tested so methods declared with either
+// receiver kind can be called. A value is copied into a new pointer (so the methods
+// can't change the caller's value); pointers are returned unchanged.
+func objectPointer(testObject interface{}) interface{} {
+
+	v := reflect.ValueOf(testObject)
+	if !v.IsValid() || v.Kind() == reflect.Pointer {
+		return testObject
+	}
+
+	pointer := reflect.New(v.Type())
+	pointer.Elem().Set(v)
+
+	return pointer.Interface()
+}
+
+// Returns the name of the object's type (e.g., "Account" for both Account{} and &Account{})
+func objectTypeName(testObject interface{}) string {
+
+	objectType := reflect.TypeOf(testObject)
+	if objectType == nil {
+		return "<nil>"
+	}
+
+	if objectType.Kind() == reflect.Pointer {
+		objectType = objectType.Elem()
+	}
+
+	return objectType.Name()
+}
+
+// Checks that the method was declared with the required kind of receiver.
+// Returns an error message if it wasn't ("" if it was or any kind is allowed).
+func checkReceiver(testObject interface{}, name string, receiver ReceiverKind) string {
+
+	if receiver == AnyReceiver {
+		return ""
+	}
+
+	objectType := reflect.TypeOf(testObject)
+	if objectType.Kind() == reflect.Pointer {
+		objectType = objectType.Elem()
+	}
+
+	// Methods with value receivers are in the value type's method set,
+	// methods with pointer receivers are only in the pointer type's
+	_, valueReceiver := objectType.MethodByName(name)
+
+	typeName := objectType.Name()
+
+	switch {
+	case receiver == PointerReceiver && valueReceiver:
+		return typeName + " method '" + name + "' must use a pointer receiver (e.g., func (x *" + typeName + ") " + name +
+			"(...)) so the changes it makes to the " + typeName + " are kept"
+	case receiver == ValueReceiver && !valueReceiver:
+		return typeName + " method '" + name + "' must use a value receiver (e.g., func (x " + typeName + ") " + name + "(...))"
+	}
+
+	return ""
+}
@@ -0,0 +1,126 @@
+package helpers
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Runs function output tests where the expected values come from a reference
+// implementation (e.g., the instructor's solution) instead of the test itself.
+// The reference is called with the same Args, StdinStrings and randomSeed as the
+// function being tested, and its returns and output are used for any Returns or
+// StdoutStrings the test leaves empty (StdoutMatchers still take priority).
+// The reference must have the same signature as the function being tested.
+func RunFunctionReferenceTests(testFuncs []FuncOutputTest, reference interface{}, randomSeed int64, t *testing.T) {
+	RunFunctionReferenceTestsWithMode(testFuncs, reference, randomSeed, DefaultFailureMode, t)
+}
+
+// Runs function output tests against a reference implementation using the specified failure mode
+func RunFunctionReferenceTestsWithMode(testFuncs []FuncOutputTest, reference interface{}, randomSeed int64, mode FailureMode, t *testing.T) {
+
+	function := reflect.ValueOf(reference)
+
+	if function.Kind() != reflect.Func {
+		t.Fatal("Reference implementation is not a function")
+	}
+
+	tests := make([]FuncOutputTest, len(testFuncs))
+
+	for i, test := range testFuncs {
+
+		result := referenceCase("Reference function for '"+test.Name+"'", function, test.Args, test.Variadic, test.StdinStrings, test.Timeout).execute(randomSeed)
+
+		if message := referenceFailure("Reference function for '"+test.Name+"'", result); message != "" {
+			t.Fatal(message)
+		}
+
+		test.Returns, test.StdoutStrings = referenceExpectations(test.Returns, test.StdoutStrings, test.StdoutMatchers, result)
+		tests[i] = test
+	}
+
+	RunFunctionOutputTestsWithMode(tests, randomSeed, mode, t)
+}
+
+// Runs method output tests where the expected values come from calling the same
+// methods on a reference object (e.g., an object of the instructor's type).
+// Both objects should be newly created and in the same starting state: every method
+// test is run on the reference object first (in order) to work out the expected
+// values, then the tests are run on testObject as in RunMethodOutputTests.
+// Each object can be a value or a pointer (values are copied, see RunMethodOutputTests).
+func RunMethodReferenceTests(testObject interface{}, referenceObject interface{}, methodTests []MethodOutputTest, randomSeed int64, t *testing.T) {
+	RunMethodReferenceTestsWithMode(testObject, referenceObject, methodTests, randomSeed, DefaultFailureMode, t)
+}
+
+// Runs method output tests against a reference object using the specified failure mode
+func RunMethodReferenceTestsWithMode(testObject interface{}, referenceObject interface{}, methodTests []MethodOutputTest, randomSeed int64, mode FailureMode, t *testing.T) {
+
+	referenceObject = objectPointer(referenceObject)
+
+	tests := make([]MethodOutputTest, len(methodTests))
+
+	for i, test := range methodTests {
+
+		description := "Reference method '" + test.Name + "'"
+
+		method := reflect.ValueOf(referenceObject).MethodByName(test.Name)
+		if !method.IsValid() {
+			t.Fatal(description + " does not exist")
+		}
+
+		result := referenceCase(description, method, test.Args, test.Variadic, test.StdinStrings, test.Timeout).execute(randomSeed)
+
+		if message := referenceFailure(description, result); message != "" {
+			t.Fatal(message)
+		}
+
+		test.Returns, test.StdoutStrings = referenceExpectations(test.Returns, test.StdoutStrings, test.StdoutMatchers, result)
+		tests[i] = test
+	}
+
+	RunMethodOutputTestsWithMode(testObject, tests, randomSeed, mode, t)
+}
+
+// Builds the case used to call the reference implementation
+func referenceCase(description string, function reflect.Value, args []reflect.Value, variadic bool, stdinStrings []string, timeout time.Duration) outputCase {
+	return outputCase{
+		description:  description,
+		function:     function,
+		args:         args,
+		variadic:     variadic,
+		stdinStrings: stdinStrings,
+		timeout:      timeout,
+	}
+}
+
+// Returns a message describing why the reference implementation failed ("" if it didn't)
+func referenceFailure(description string, result execution) string {
+
+	if result.timedOut {
+		return description + " did not complete in time. Check the test's Args and StdinStrings."
+	}
+
+	if result.panicked {
+		return description + " caused a runtime error. Check the test's Args and StdinStrings."
+	}
+
+	return ""
+}
+
+// Returns the expected returns and stdout strings, filling in
+// the ones not already provided using the reference's results
+func referenceExpectations(returns []reflect.Value, stdoutStrings []string, stdoutMatchers []OutputMatcher, result execution) ([]reflect.Value, []string) {
+
+	if len(returns) == 0 {
+		returns = result.returnVals
+	}
+
+	if len(stdoutStrings) == 0 && len(stdoutMatchers) == 0 {
+		for _, line := range result.stdout {
+			stdoutStrings = append(stdoutStrings, strings.TrimSpace(line))
+		}
+	}
+
+	return returns, stdoutStrings
+}
@@ -0,0 +1,442 @@
+package helpers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Receives a structured record of every check run by the runners (function/method
+// anatomy and output tests, the static code checks and ScoreCheck).
+// Results are reported as they're recorded and written out when Flush is called
+// (RunWithScore flushes the registered reporters after all of the tests have run).
+type Reporter interface {
+	Report(entry ScoreEntry)
+	Flush() error
+}
+
+// Gradescope visibility used for tests with HideExpected set
+var HiddenTestVisibility = "after_published"
+
+// Environment variable used to register reporters that write to files.
+// Holds a comma separated list of format:fileName pairs, where format is
+// json, junit, csv or gradescope (e.g., HELPERS_REPORTS=junit:report.xml,gradescope:results.json)
+const ReportsEnvVar = "HELPERS_REPORTS"
+
+var reporters = struct {
+	sync.Mutex
+	list []Reporter
+}{}
+
+func init() {
+
+	for _, r := range settingReporters(os.Getenv(ReportsEnvVar)) {
+		AddReporter(r)
+	}
+}
+
+// Creates the file reporters listed in a ReportsEnvVar value.
+// Invalid entries are skipped (see invalidSetting).
+func settingReporters(value string) []Reporter {
+
+	if value == "" {
+		return nil
+	}
+
+	var list []Reporter
+
+	for _, report := range strings.Split(value, ",") {
+
+		format, fileName, ok := strings.Cut(strings.TrimSpace(report), ":")
+
+		var newReporter func(w io.Writer) Reporter
+
+		switch format {
+		case "json":
+			newReporter = NewJSONReporter
+		case "junit":
+			newReporter = NewJUnitReporter
+		case "csv":
+			newReporter = func(w io.Writer) Reporter { return NewCSVReporter(w, true) }
+		case "gradescope":
+			newReporter = NewGradescopeReporter
+		}
+
+		if !ok || fileName == "" || newReporter == nil {
+			invalidSetting(ReportsEnvVar, report, "format:fileName where format is json, junit, csv or gradescope")
+			continue
+		}
+
+		list = append(list, FileReporter(fileName, newReporter))
+	}
+
+	return list
+}
+
+// Registers a reporter that receives every result recorded from now on
+func AddReporter(r Reporter) {
+
+	reporters.Lock()
+	defer reporters.Unlock()
+
+	reporters.list = append(reporters.list, r)
+}
+
+// Flushes every registered reporter.
+// Returns the errors from all of the reporters that failed (nil if none did).
+func FlushReporters() error {
+
+	reporters.Lock()
+	defer reporters.Unlock()
+
+	var errs []error
+
+	for _, r := range reporters.list {
+		if err := r.Flush(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Adds the result to the DefaultScorecard and sends it to the registered reporters
+func publishResult(entry ScoreEntry) {
+
+	entry = DefaultScorecard.Add(entry)
+
+	reporters.Lock()
+	defer reporters.Unlock()
+
+	for _, r := range reporters.list {
+		r.Report(entry)
+	}
+}
+
+// Results collected by the built-in reporters
+type reportedResults struct {
+	mu      sync.Mutex
+	entries []ScoreEntry
+}
+
+func (r *reportedResults) Report(entry ScoreEntry) {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entries = append(r.entries, entry)
+}
+
+// Returns the collected entries along with the total points earned and possible
+func (r *reportedResults) results() ([]ScoreEntry, float64, float64) {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	earned, possible := 0.0, 0.0
+
+	for _, entry := range r.entries {
+		earned += entry.Earned
+		possible += entry.Points
+	}
+
+	return append([]ScoreEntry(nil), r.entries...), earned, possible
+}
+
+// Writes the results as a single JSON document
+type jsonReporter struct {
+	reportedResults
+	w io.Writer
+}
+
+// Creates a reporter that writes the results as JSON:
+//
+//	{"student": "...", "score": 8, "max_score": 10, "results": [{"name": "...", "passed": false, ...}]}
+func NewJSONReporter(w io.Writer) Reporter {
+	return &jsonReporter{w: w}
+}
+
+type jsonResult struct {
+	Name       string   `json:"name"`
+	Category   string   `json:"category"`
+	Test       string   `json:"test"`
+	Passed     bool     `json:"passed"`
+	Score      float64  `json:"score"`
+	MaxScore   float64  `json:"max_score"`
+	Visibility string   `json:"visibility"`
+	Messages   []string `json:"messages"`
+	Seconds    float64  `json:"duration_seconds"`
+}
+
+func (r *jsonReporter) Flush() error {
+
+	entries, earned, possible := r.results()
+
+	report := struct {
+		Student  string       `json:"student,omitempty"`
+		Score    float64      `json:"score"`
+		MaxScore float64      `json:"max_score"`
+		Results  []jsonResult `json:"results"`
+	}{
+		Student:  DefaultScorecard.Student,
+		Score:    earned,
+		MaxScore: possible,
+		Results:  []jsonResult{},
+	}
+
+	for _, entry := range entries {
+		report.Results = append(report.Results, jsonResult{
+			Name:       entry.Name,
+			Category:   entry.Category,
+			Test:       entry.Test,
+			Passed:     entry.Passed,
+			Score:      entry.Earned,
+			MaxScore:   entry.Points,
+			Visibility: entry.Visibility,
+			Messages:   append([]string{}, entry.Messages...),
+			Seconds:    entry.Duration.Seconds(),
+		})
+	}
+
+	encoder := json.NewEncoder(r.w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(report)
+}
+
+// Writes the results as a JUnit XML report (one test suite per category)
+type junitReporter struct {
+	reportedResults
+	w io.Writer
+}
+
+// Creates a reporter that writes the results in the JUnit XML format
+// understood by most CI systems and LMS tools
+func NewJUnitReporter(w io.Writer) Reporter {
+	return &junitReporter{w: w}
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr,omitempty"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Time     float64          `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Time     float64         `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      float64       `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+func (r *junitReporter) Flush() error {
+
+	entries, _, _ := r.results()
+
+	report := junitTestSuites{Name: DefaultScorecard.Student}
+	suites := map[string]int{}
+
+	for _, entry := range entries {
+
+		index, ok := suites[entry.Category]
+		if !ok {
+			index = len(report.Suites)
+			suites[entry.Category] = index
+			report.Suites = append(report.Suites, junitTestSuite{Name: entry.Category})
+		}
+
+		testCase := junitTestCase{
+			Name:      entry.Name,
+			ClassName: entry.Category,
+			Time:      entry.Duration.Seconds(),
+		}
+
+		suite := &report.Suites[index]
+
+		if !entry.Passed {
+
+			message := "Failed"
+			if len(entry.Messages) > 0 {
+				message = strings.SplitN(entry.Messages[0], "\n", 2)[0]
+			}
+
+			testCase.Failure = &junitFailure{Message: message, Text: strings.Join(entry.Messages, "\n\n")}
+
+			suite.Failures++
+			report.Failures++
+		}
+
+		suite.Cases = append(suite.Cases, testCase)
+		suite.Tests++
+		suite.Time += testCase.Time
+
+		report.Tests++
+		report.Time += testCase.Time
+	}
+
+	if _, err := io.WriteString(r.w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(r.w)
+	encoder.Indent("", "  ")
+
+	if err := encoder.Encode(report); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(r.w, "\n")
+
+	return err
+}
+
+// Writes the results as a gradebook row
+type csvReporter struct {
+	reportedResults
+	w      io.Writer
+	header bool
+}
+
+// Creates a reporter that writes the results as a CSV gradebook row:
+// student, score, max score and then the points earned for each check.
+// If header is true, a header row (holding the check names) is written first.
+func NewCSVReporter(w io.Writer, header bool) Reporter {
+	return &csvReporter{w: w, header: header}
+}
+
+func (r *csvReporter) Flush() error {
+
+	entries, earned, possible := r.results()
+
+	writer := csv.NewWriter(r.w)
+
+	if r.header {
+
+		columns := []string{"student", "score", "max_score"}
+		for _, entry := range entries {
+			columns = append(columns, entry.Name)
+		}
+
+		writer.Write(columns)
+	}
+
+	row := []string{DefaultScorecard.Student, formatPoints(earned), formatPoints(possible)}
+	for _, entry := range entries {
+		row = append(row, formatPoints(entry.Earned))
+	}
+
+	writer.Write(row)
+	writer.Flush()
+
+	return writer.Error()
+}
+
+// Writes the results using the Gradescope autograder results.json schema
+type gradescopeReporter struct {
+	reportedResults
+	w io.Writer
+}
+
+// Creates a reporter that writes the results in the Gradescope autograder format.
+// Gradescope reads the file from /autograder/results/results.json.
+func NewGradescopeReporter(w io.Writer) Reporter {
+	return &gradescopeReporter{w: w}
+}
+
+type gradescopeTest struct {
+	Name       string  `json:"name"`
+	Score      float64 `json:"score"`
+	MaxScore   float64 `json:"max_score"`
+	Status     string  `json:"status"`
+	Visibility string  `json:"visibility"`
+	Output     string  `json:"output"`
+}
+
+func (r *gradescopeReporter) Flush() error {
+
+	entries, earned, _ := r.results()
+
+	report := struct {
+		Score float64          `json:"score"`
+		Tests []gradescopeTest `json:"tests"`
+	}{
+		Score: earned,
+		Tests: []gradescopeTest{},
+	}
+
+	for _, entry := range entries {
+
+		status := "passed"
+		if !entry.Passed {
+			status = "failed"
+		}
+
+		report.Tests = append(report.Tests, gradescopeTest{
+			Name:       entry.Name,
+			Score:      entry.Earned,
+			MaxScore:   entry.Points,
+			Status:     status,
+			Visibility: entry.Visibility,
+			Output:     strings.Join(entry.Messages, "\n\n"),
+		})
+	}
+
+	encoder := json.NewEncoder(r.w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(report)
+}
+
+// Reporter that creates a file and writes another reporter's output to it
+type fileReporter struct {
+	fileName string
+	buffer   *strings.Builder
+	reporter Reporter
+}
+
+// Creates a reporter that writes to the specified file
+// (e.g., FileReporter("results.json", NewGradescopeReporter)).
+// The file isn't created until the reporter is flushed, so isolated
+// child processes never overwrite it.
+func FileReporter(fileName string, newReporter func(w io.Writer) Reporter) Reporter {
+
+	buffer := &strings.Builder{}
+
+	return &fileReporter{fileName: fileName, buffer: buffer, reporter: newReporter(buffer)}
+}
+
+func (r *fileReporter) Report(entry ScoreEntry) {
+	r.reporter.Report(entry)
+}
+
+func (r *fileReporter) Flush() error {
+
+	r.buffer.Reset()
+
+	if err := r.reporter.Flush(); err != nil {
+		return err
+	}
+
+	return os.WriteFile(r.fileName, []byte(r.buffer.String()), 0644)
+}
@@ -0,0 +1,202 @@
+package helpers
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Results reported to each of the reporters in the golden output tests
+var reporterEntries = []ScoreEntry{
+	{Name: "Sum", Category: "Output", Points: 2, Earned: 2, Passed: true, Test: "TestSum", Visibility: "visible", Duration: 1500 * time.Millisecond},
+	{Name: "Average \"mean\"", Category: "Hidden", Points: 1, Passed: false, Test: "TestAverage/case_1",
+		Visibility: HiddenTestVisibility, Messages: []string{"Function 'Average' returned unexpected value.\nExpected 2", "Second <problem>"}},
+	{Name: "Style", Category: "Output", Points: 0, Passed: true, Test: "TestStyle", Visibility: "visible"},
+}
+
+func TestReporterGoldenOutput(t *testing.T) {
+
+	defer func(student string) { DefaultScorecard.Student = student }(DefaultScorecard.Student)
+	DefaultScorecard.Student = "jdoe"
+
+	tests := []struct {
+		name     string
+		reporter func(w io.Writer) Reporter
+		golden   string
+	}{
+		{name: "json", reporter: NewJSONReporter, golden: `{
+  "student": "jdoe",
+  "score": 2,
+  "max_score": 3,
+  "results": [
+    {
+      "name": "Sum",
+      "category": "Output",
+      "test": "TestSum",
+      "passed": true,
+      "score": 2,
+      "max_score": 2,
+      "visibility": "visible",
+      "messages": [],
+      "duration_seconds": 1.5
+    },
+    {
+      "name": "Average \"mean\"",
+      "category": "Hidden",
+      "test": "TestAverage/case_1",
+      "passed": false,
+      "score": 0,
+      "max_score": 1,
+      "visibility": "after_published",
+      "messages": [
+        "Function 'Average' returned unexpected value.\nExpected 2",
+        "Second \u003cproblem\u003e"
+      ],
+      "duration_seconds": 0
+    },
+    {
+      "name": "Style",
+      "category": "Output",
+      "test": "TestStyle",
+      "passed": true,
+      "score": 0,
+      "max_score": 0,
+      "visibility": "visible",
+      "messages": [],
+      "duration_seconds": 0
+    }
+  ]
+}
+`},
+		{name: "junit", reporter: NewJUnitReporter, golden: `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="jdoe" tests="3" failures="1" time="1.5">
+  <testsuite name="Output" tests="2" failures="0" time="1.5">
+    <testcase name="Sum" classname="Output" time="1.5"></testcase>
+    <testcase name="Style" classname="Output" time="0"></testcase>
+  </testsuite>
+  <testsuite name="Hidden" tests="1" failures="1" time="0">
+    <testcase name="Average &#34;mean&#34;" classname="Hidden" time="0">
+      <failure message="Function &#39;Average&#39; returned unexpected value.">Function &#39;Average&#39; returned unexpected value.&#xA;Expected 2&#xA;&#xA;Second &lt;problem&gt;</failure>
+    </testcase>
+  </testsuite>
+</testsuites>
+`},
+		{name: "csv", reporter: func(w io.Writer) Reporter { return NewCSVReporter(w, true) }, golden: `student,score,max_score,Sum,"Average ""mean""",Style
+jdoe,2,3,2,0,0
+`},
+		{name: "gradescope", reporter: NewGradescopeReporter, golden: `{
+  "score": 2,
+  "tests": [
+    {
+      "name": "Sum",
+      "score": 2,
+      "max_score": 2,
+      "status": "passed",
+      "visibility": "visible",
+      "output": ""
+    },
+    {
+      "name": "Average \"mean\"",
+      "score": 0,
+      "max_score": 1,
+      "status": "failed",
+      "visibility": "after_published",
+      "output": "Function 'Average' returned unexpected value.\nExpected 2\n\nSecond \u003cproblem\u003e"
+    },
+    {
+      "name": "Style",
+      "score": 0,
+      "max_score": 0,
+      "status": "passed",
+      "visibility": "visible",
+      "output": ""
+    }
+  ]
+}
+`},
+	}
+
+	for _, test := range tests {
+
+		var buffer bytes.Buffer
+		r := test.reporter(&buffer)
+
+		for _, entry := range reporterEntries {
+			r.Report(entry)
+		}
+
+		if err := r.Flush(); err != nil {
+			t.Errorf("%s: unexpected error %v", test.name, err)
+			continue
+		}
+
+		if buffer.String() != test.golden {
+			t.Errorf("%s: got\n%s\nexpected\n%s", test.name, buffer.String(), test.golden)
+		}
+	}
+}
+
+func TestSettingReporters(t *testing.T) {
+
+	// Keep the problems recorded here from being reported by other tests
+	settingProblems.Lock()
+	recorded := len(settingProblems.messages)
+	settingProblems.Unlock()
+
+	defer func() {
+		settingProblems.Lock()
+		settingProblems.messages = settingProblems.messages[:recorded]
+		settingProblems.Unlock()
+	}()
+
+	dir := t.TempDir()
+	fileName := filepath.Join(dir, "results.json")
+
+	list := settingReporters("gradescope:" + fileName + ", xml:report.xml,junit:,csv")
+
+	if len(list) != 1 {
+		t.Fatalf("expected 1 reporter, got %d", len(list))
+	}
+
+	settingProblems.Lock()
+	problems := append([]string(nil), settingProblems.messages[recorded:]...)
+	settingProblems.Unlock()
+
+	expected := []string{
+		"Invalid HELPERS_REPORTS value \" xml:report.xml\". Expected format:fileName where format is json, junit, csv or gradescope. The value was ignored.",
+		"Invalid HELPERS_REPORTS value \"junit:\". Expected format:fileName where format is json, junit, csv or gradescope. The value was ignored.",
+		"Invalid HELPERS_REPORTS value \"csv\". Expected format:fileName where format is json, junit, csv or gradescope. The value was ignored.",
+	}
+
+	if strings.Join(problems, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("got problems\n%s\nexpected\n%s", strings.Join(problems, "\n"), strings.Join(expected, "\n"))
+	}
+
+	// The file isn't written until the reporter is flushed
+	list[0].Report(reporterEntries[0])
+
+	if _, err := os.Stat(fileName); !os.IsNotExist(err) {
+		t.Errorf("expected %s not to exist before flushing, got %v", fileName, err)
+	}
+
+	if err := list[0].Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(data), "\"name\": \"Sum\"") {
+		t.Errorf("expected the Gradescope results in %s, got\n%s", fileName, data)
+	}
+
+	if len(settingReporters("")) != 0 {
+		t.Error("expected no reporters for an empty setting")
+	}
+}
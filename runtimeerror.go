@@ -0,0 +1,235 @@
+package helpers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Audience a runtime error (panic) report is written for
+type Audience int
+
+// Audience enum values
+const (
+	StudentAudience Audience = iota // panic message and the line of the submission it occurred on (with a short snippet)
+	TAAudience                      // also the submission's call stack and the full stack trace
+)
+
+// Audience used when reporting runtime errors.
+// Initialized from the AudienceEnvVar environment variable (if set).
+var RuntimeErrorAudience = StudentAudience
+
+// Environment variable used to initialize RuntimeErrorAudience (e.g., HELPERS_AUDIENCE=ta)
+const AudienceEnvVar = "HELPERS_AUDIENCE"
+
+// Directory holding the submission's source files. Only stack frames from files
+// inside it are reported ("" = the module containing the working directory).
+// Test files (_test.go), vendored code and this package are never reported.
+var SubmissionDir = ""
+
+// Number of source lines shown before and after the line a runtime error occurred on
+const snippetContext = 2
+
+// Import path of this package (its stack frames aren't part of the submission)
+var helpersPackage = reflect.TypeOf(StudentAudience).PkgPath()
+
+func init() {
+
+	if value, ok := os.LookupEnv(AudienceEnvVar); ok {
+
+		switch strings.ToLower(value) {
+		case "student":
+			RuntimeErrorAudience = StudentAudience
+		case "ta":
+			RuntimeErrorAudience = TAAudience
+		default:
+			invalidSetting(AudienceEnvVar, value, "student or ta")
+		}
+	}
+}
+
+// Builds the runtime error message for a recovered panic.
+// Must be called from the deferred function that recovered the panic
+// (the stack still holds the frames that caused it).
+func runtimeErrorReport(value interface{}) string {
+
+	pcs := make([]uintptr, 64)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+
+	var stack []runtime.Frame
+	var submission []runtime.Frame
+
+	for {
+		frame, more := frames.Next()
+
+		stack = append(stack, frame)
+
+		if isSubmissionFile(frame) {
+			submission = append(submission, frame)
+		}
+
+		if !more {
+			break
+		}
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString("A runtime error occurred while attempting to run this unit test.")
+	sb.WriteString("\nPanic: " + panicValueText(value))
+
+	if len(submission) > 0 {
+		sb.WriteString("\nLocation: " + frameLocation(submission[0]))
+		sb.WriteString(sourceSnippet(submission[0].File, submission[0].Line))
+	} else {
+		sb.WriteString("\nThere are a variety of situations that can cause runtime errors (e.g., accessing array index out of range, dereferencing a nil pointer, etc.).")
+	}
+
+	if RuntimeErrorAudience == TAAudience {
+
+		if len(submission) > 1 {
+			sb.WriteString("\nSubmission call stack:")
+			for _, frame := range submission {
+				sb.WriteString("\n  " + frameLocation(frame))
+			}
+		}
+
+		sb.WriteString("\nStack trace:")
+		for _, frame := range stack {
+			sb.WriteString("\n  " + frame.Function + "\n    " + frame.File + ":" + strconv.Itoa(frame.Line))
+		}
+	}
+
+	sb.WriteString("\nReview your code and/or contact the instructor for assistance.")
+
+	return sb.String()
+}
+
+// Describes the panic value (errors and strings are shown as their text)
+func panicValueText(value interface{}) string {
+
+	switch v := value.(type) {
+	case error:
+		return v.Error()
+	case string:
+		return v
+	}
+
+	return fmt.Sprintf("%v", value)
+}
+
+// Returns true if the frame belongs to one of the submission's source files
+func isSubmissionFile(frame runtime.Frame) bool {
+
+	if frame.File == "" || strings.HasSuffix(frame.File, "_test.go") ||
+		strings.HasPrefix(frame.Function, helpersPackage+".") {
+		return false
+	}
+
+	dir := submissionDir()
+	if dir == "" {
+		return false
+	}
+
+	relative, err := filepath.Rel(dir, filepath.FromSlash(frame.File))
+	if err != nil || relative == ".." || strings.HasPrefix(relative, ".."+string(filepath.Separator)) {
+		return false
+	}
+
+	return !strings.HasPrefix(relative, "vendor"+string(filepath.Separator))
+}
+
+// Returns SubmissionDir or, if it isn't set, the root of the module containing
+// the working directory (the working directory if there's no go.mod)
+func submissionDir() string {
+
+	if SubmissionDir != "" {
+		dir, _ := filepath.Abs(SubmissionDir)
+		return dir
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+
+	for dir := wd; ; dir = filepath.Dir(dir) {
+
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir
+		}
+
+		if filepath.Dir(dir) == dir {
+			return wd
+		}
+	}
+}
+
+// Describes where the frame is (e.g., "calc.go:12 in Sum"). The file name is relative to the working directory when possible.
+func frameLocation(frame runtime.Frame) string {
+
+	file := filepath.FromSlash(frame.File)
+
+	if wd, err := os.Getwd(); err == nil {
+		if relative, err := filepath.Rel(wd, file); err == nil {
+			file = relative
+		}
+	}
+
+	function := frame.Function
+	if slash := strings.LastIndex(function, "/"); slash >= 0 {
+		function = function[slash+1:]
+	}
+
+	if dot := strings.Index(function, "."); dot >= 0 {
+		function = function[dot+1:]
+	}
+
+	return file + ":" + strconv.Itoa(frame.Line) + " in " + function
+}
+
+// Returns the lines around the specified line with the line itself marked
+// ("" if the file can't be read)
+func sourceSnippet(file string, line int) string {
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return ""
+	}
+
+	lines := strings.Split(string(data), "\n")
+	if line < 1 || line > len(lines) {
+		return ""
+	}
+
+	first := line - snippetContext
+	if first < 1 {
+		first = 1
+	}
+
+	last := line + snippetContext
+	if last > len(lines) {
+		last = len(lines)
+	}
+
+	width := len(strconv.Itoa(last))
+
+	var sb strings.Builder
+
+	for n := first; n <= last; n++ {
+
+		marker := "  "
+		if n == line {
+			marker = "> "
+		}
+
+		number := strconv.Itoa(n)
+		sb.WriteString("\n  " + marker + strings.Repeat(" ", width-len(number)) + number + " | " + strings.TrimRight(lines[n-1], "\r"))
+	}
+
+	return sb.String()
+}
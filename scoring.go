@@ -0,0 +1,348 @@
+package helpers
+
+import (
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Number of points a check is worth when it doesn't specify its own Points
+var DefaultPoints = 1.0
+
+// Environment variable used to initialize DefaultScorecard.Student (e.g., HELPERS_STUDENT=jdoe)
+const StudentEnvVar = "HELPERS_STUDENT"
+
+// Result of a single scored check
+type ScoreEntry struct {
+	Name       string
+	Category   string
+	Points     float64 // points possible
+	Earned     float64
+	Passed     bool
+	Messages   []string
+	Test       string        // name of the go test (or subtest) that ran the check
+	Visibility string        // "visible" or HiddenTestVisibility for tests with HideExpected set
+	Duration   time.Duration // time spent running the check (0 if not measured)
+}
+
+// Points earned and possible for a category
+type CategoryScore struct {
+	Category string
+	Earned   float64
+	Possible float64
+}
+
+// Accumulates the results of every check run for a student.
+// Results are recorded whether or not the testing.T has already failed,
+// so one early failure doesn't hide the rest of the score.
+type Scorecard struct {
+	Student string
+
+	mu      sync.Mutex
+	entries []ScoreEntry
+	names   map[string]int
+}
+
+// Scorecard used by all of the runners
+var DefaultScorecard = NewScorecard(os.Getenv(StudentEnvVar))
+
+// Creates an empty scorecard for the specified student
+func NewScorecard(student string) *Scorecard {
+	return &Scorecard{Student: student, names: map[string]int{}}
+}
+
+// Adds a result to the scorecard and returns the entry as it was added.
+// Entries with duplicate names are numbered (e.g., "Sum", "Sum (2)").
+func (s *Scorecard) Add(entry ScoreEntry) ScoreEntry {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.names[entry.Name]++
+	if count := s.names[entry.Name]; count > 1 {
+		entry.Name += " (" + strconv.Itoa(count) + ")"
+	}
+
+	s.entries = append(s.entries, entry)
+
+	return entry
+}
+
+// Returns a copy of every result recorded so far
+func (s *Scorecard) Entries() []ScoreEntry {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]ScoreEntry(nil), s.entries...)
+}
+
+// Removes all recorded results
+func (s *Scorecard) Reset() {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries = nil
+	s.names = map[string]int{}
+}
+
+// Returns the total points earned and possible
+func (s *Scorecard) Score() (float64, float64) {
+
+	earned, possible := 0.0, 0.0
+
+	for _, entry := range s.Entries() {
+		earned += entry.Earned
+		possible += entry.Points
+	}
+
+	return earned, possible
+}
+
+// Returns the points earned and possible for each category (sorted by category name)
+func (s *Scorecard) Categories() []CategoryScore {
+
+	totals := map[string]*CategoryScore{}
+
+	for _, entry := range s.Entries() {
+
+		total, ok := totals[entry.Category]
+		if !ok {
+			total = &CategoryScore{Category: entry.Category}
+			totals[entry.Category] = total
+		}
+
+		total.Earned += entry.Earned
+		total.Possible += entry.Points
+	}
+
+	var categories []CategoryScore
+	for _, total := range totals {
+		categories = append(categories, *total)
+	}
+
+	sort.Slice(categories, func(i int, j int) bool {
+		return categories[i].Category < categories[j].Category
+	})
+
+	return categories
+}
+
+// Returns a printable breakdown of the score by category and check
+func (s *Scorecard) Breakdown() string {
+
+	var sb strings.Builder
+
+	earned, possible := s.Score()
+
+	if s.Student != "" {
+		sb.WriteString("Student: " + s.Student + "\n")
+	}
+
+	sb.WriteString("Score: " + formatPoints(earned) + " / " + formatPoints(possible) + "\n")
+
+	entries := s.Entries()
+
+	for _, category := range s.Categories() {
+
+		sb.WriteString("\n" + category.Category + ": " + formatPoints(category.Earned) + " / " + formatPoints(category.Possible) + "\n")
+
+		for _, entry := range entries {
+
+			if entry.Category != category.Category {
+				continue
+			}
+
+			status := "PASS"
+			if !entry.Passed {
+				status = "FAIL"
+			}
+
+			sb.WriteString("  [" + status + "] " + entry.Name + " (" + formatPoints(entry.Earned) + " / " + formatPoints(entry.Points) + ")\n")
+		}
+	}
+
+	return sb.String()
+}
+
+// Formats a point value without unneeded decimal places
+func formatPoints(points float64) string {
+	return strconv.FormatFloat(points, 'f', -1, 64)
+}
+
+// Runs the tests, prints the DefaultScorecard breakdown and flushes
+// the registered reporters afterwards. Meant to be used in TestMain:
+//
+//	func TestMain(m *testing.M) {
+//		os.Exit(helpers.RunWithScore(m))
+//	}
+func RunWithScore(m *testing.M) int {
+
+	code := m.Run()
+
+	os.Stdout.WriteString("\n" + DefaultScorecard.Breakdown())
+
+	if err := FlushReporters(); err != nil {
+		os.Stderr.WriteString("Unable to write test results: " + err.Error() + "\n")
+
+		if code == 0 {
+			code = 1
+		}
+	}
+
+	return code
+}
+
+// Creates the score entry for a check.
+// Category and points fall back to the defaults when not specified.
+// Ungraded checks are worth no points whatever points is set to.
+func newScoreEntry(name string, category string, defaultCategory string, points float64, ungraded bool, messages []string) ScoreEntry {
+
+	if category == "" {
+		category = defaultCategory
+	}
+
+	if ungraded {
+		points = 0
+	} else if points == 0 {
+		points = DefaultPoints
+	}
+
+	entry := ScoreEntry{
+		Name:       name,
+		Category:   category,
+		Points:     points,
+		Passed:     len(messages) == 0,
+		Messages:   messages,
+		Visibility: "visible",
+	}
+
+	if entry.Passed {
+		entry.Earned = points
+	}
+
+	return entry
+}
+
+// Creates the score entry for an output test.
+// Hidden tests (HideExpected set) use HiddenTestVisibility.
+func outputScoreEntry(name string, category string, points float64, ungraded bool, hidden bool, messages []string) ScoreEntry {
+
+	entry := newScoreEntry(name, category, "Output", points, ungraded, messages)
+
+	if hidden {
+		entry.Visibility = HiddenTestVisibility
+	}
+
+	return entry
+}
+
+// Checks wrapped by ScoreCheck that are still running, in the order they started
+// (a nested ScoreCheck always comes after the one it's running in)
+var scoredChecks = struct {
+	sync.Mutex
+	active []*ScoreEntry
+}{}
+
+// Returns the innermost ScoreCheck entry the test (or subtest) is running in, if any
+func activeScoredCheck(t *testing.T) *ScoreEntry {
+
+	scoredChecks.Lock()
+	defer scoredChecks.Unlock()
+
+	for i := len(scoredChecks.active) - 1; i >= 0; i-- {
+
+		entry := scoredChecks.active[i]
+
+		if t.Name() == entry.Test || strings.HasPrefix(t.Name(), entry.Test+"/") {
+			return entry
+		}
+	}
+
+	return nil
+}
+
+// Removes the entry from the ScoreCheck entries that are still running
+func finishScoredCheck(entry *ScoreEntry) {
+
+	scoredChecks.Lock()
+	defer scoredChecks.Unlock()
+
+	for i, active := range scoredChecks.active {
+		if active == entry {
+			scoredChecks.active = append(scoredChecks.active[:i], scoredChecks.active[i+1:]...)
+			return
+		}
+	}
+}
+
+// Records the result of a check in the DefaultScorecard and, if report is true,
+// reports each of its problems to t. Checks run inside ScoreCheck are folded
+// into the ScoreCheck's entry instead of being scored on their own.
+// The first result recorded also reports any setting problems (see invalidSetting).
+func recordResult(t *testing.T, entry ScoreEntry, report bool) {
+
+	reportSettingProblems(t)
+
+	entry.Test = t.Name()
+
+	if scoped := activeScoredCheck(t); scoped != nil {
+
+		scoredChecks.Lock()
+		scoped.Messages = append(scoped.Messages, entry.Messages...)
+		scoredChecks.Unlock()
+
+	} else {
+		publishResult(entry)
+	}
+
+	if report {
+		for _, message := range entry.Messages {
+			t.Error(message)
+		}
+	}
+}
+
+// Runs the check as a subtest worth the specified number of points
+// (0 for a check that's reported but worth no points).
+// Any check can be scored this way (e.g., the static code checks or custom
+// test code). Results from runners called inside the check count toward
+// this single entry instead of being scored separately (when checks are
+// nested, toward the innermost one).
+// Returns true if the check passed.
+func ScoreCheck(t *testing.T, name string, category string, points float64, check func(t *testing.T)) bool {
+
+	entry := newScoreEntry(name, category, "Other", points, points == 0, nil)
+	start := time.Now()
+
+	return t.Run(name, func(t *testing.T) {
+
+		entry.Test = t.Name()
+
+		scoredChecks.Lock()
+		scoredChecks.active = append(scoredChecks.active, &entry)
+		scoredChecks.Unlock()
+
+		defer func() {
+
+			finishScoredCheck(&entry)
+
+			entry.Passed = !t.Failed()
+			entry.Earned = 0
+			if entry.Passed {
+				entry.Earned = entry.Points
+			}
+
+			entry.Duration = time.Since(start)
+
+			publishResult(entry)
+		}()
+
+		check(t)
+	})
+}
@@ -0,0 +1,93 @@
+package helpers
+
+import (
+	"testing"
+)
+
+func TestNewScoreEntryPoints(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		points   float64
+		ungraded bool
+		messages []string
+		possible float64
+		earned   float64
+	}{
+		{name: "default points", possible: DefaultPoints, earned: DefaultPoints},
+		{name: "custom points", points: 3, possible: 3, earned: 3},
+		{name: "failed", points: 3, messages: []string{"wrong"}, possible: 3, earned: 0},
+		{name: "ungraded", ungraded: true, possible: 0, earned: 0},
+		{name: "ungraded ignores points", points: 3, ungraded: true, possible: 0, earned: 0},
+	}
+
+	for _, test := range tests {
+
+		entry := newScoreEntry("Check", "", "Other", test.points, test.ungraded, test.messages)
+
+		if entry.Points != test.possible || entry.Earned != test.earned {
+			t.Errorf("%s: got %v / %v, expected %v / %v", test.name, entry.Earned, entry.Points, test.earned, test.possible)
+		}
+
+		if entry.Passed != (len(test.messages) == 0) {
+			t.Errorf("%s: Passed = %v", test.name, entry.Passed)
+		}
+	}
+}
+
+func TestNestedScoreCheck(t *testing.T) {
+
+	var outerTest, innerTest string
+	recorded := len(DefaultScorecard.Entries())
+
+	ScoreCheck(t, "outer", "Scoring", 2, func(t *testing.T) {
+
+		outerTest = t.Name()
+
+		ScoreCheck(t, "inner", "Scoring", 0, func(t *testing.T) {
+
+			innerTest = t.Name()
+
+			t.Run("step", func(t *testing.T) {
+
+				if entry := activeScoredCheck(t); entry == nil || entry.Test != innerTest {
+					t.Errorf("step: expected the inner check to be active, got %v", entry)
+				}
+
+				recordResult(t, newScoreEntry("step", "", "Other", 0, false, []string{"folded"}), false)
+			})
+		})
+
+		if entry := activeScoredCheck(t); entry == nil || entry.Test != outerTest {
+			t.Errorf("outer: expected the outer check to be active, got %v", entry)
+		}
+	})
+
+	if entry := activeScoredCheck(t); entry != nil {
+		t.Errorf("expected no active check after ScoreCheck returned, got %v", entry)
+	}
+
+	found := 0
+
+	for _, entry := range DefaultScorecard.Entries()[recorded:] {
+
+		switch entry.Test {
+
+		case outerTest:
+			found++
+			if entry.Points != 2 || len(entry.Messages) != 0 {
+				t.Errorf("outer: got %v points and messages %v, expected 2 points and no messages", entry.Points, entry.Messages)
+			}
+
+		case innerTest:
+			found++
+			if entry.Points != 0 || len(entry.Messages) != 1 || entry.Messages[0] != "folded" {
+				t.Errorf("inner: got %v points and messages %v, expected 0 points and the step's message", entry.Points, entry.Messages)
+			}
+		}
+	}
+
+	if found != 2 {
+		t.Errorf("expected entries for both checks, found %d", found)
+	}
+}
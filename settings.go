@@ -0,0 +1,42 @@
+package helpers
+
+import (
+	"sync"
+	"testing"
+)
+
+// Problems found with the environment variables used to configure the package
+// (e.g., an invalid HELPERS_TIMEOUT_SCALE). Invalid values are ignored (the defaults are used)
+// and the problems are reported as errors by the first test that records a result.
+var settingProblems = struct {
+	sync.Mutex
+	messages []string
+	reported bool
+}{}
+
+// Records an environment variable holding a value that can't be used
+func invalidSetting(envVar string, value string, expected string) {
+
+	settingProblems.Lock()
+	defer settingProblems.Unlock()
+
+	settingProblems.messages = append(settingProblems.messages, "Invalid "+envVar+" value \""+value+"\". Expected "+
+		expected+". The value was ignored.")
+}
+
+// Reports the setting problems (only the first time it's called)
+func reportSettingProblems(t *testing.T) {
+
+	settingProblems.Lock()
+	defer settingProblems.Unlock()
+
+	if settingProblems.reported {
+		return
+	}
+
+	settingProblems.reported = true
+
+	for _, message := range settingProblems.messages {
+		t.Error(message)
+	}
+}
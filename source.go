@@ -0,0 +1,370 @@
+package helpers
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"go/types"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Parsed Go source used by the static code checks.
+// Parsing once and walking the syntax tree avoids the problems that come
+// with matching raw text (braces in string literals, "//" inside strings,
+// expressions spanning several lines, etc.)
+type SourceFile struct {
+	text   string
+	fset   *token.FileSet
+	file   *ast.File
+	offset int // number of bytes added in front of text when parsing a fragment
+}
+
+// Wrapper used when the provided text is not a complete Go file
+// (e.g., a function body returned by GetFunctionBodyText)
+const fragmentPrefix = "package fragment\nfunc _() {\n"
+
+// Loads and parses the specified Go source file
+func LoadSourceFile(fileName string) (*SourceFile, error) {
+
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseSource(string(data))
+}
+
+// Parses the specified code text.
+// The text can either be a complete Go file or a list of statements
+// (e.g., the body of a function). If the text can't be fully parsed,
+// the returned SourceFile holds as much of the syntax tree as the parser
+// could recover, along with the parse error.
+func ParseSource(text string) (*SourceFile, error) {
+
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, "", text, parser.ParseComments)
+	if err == nil {
+		return &SourceFile{text: text, fset: fset, file: file}, nil
+	}
+
+	// Text isn't a complete file, so try it as a function body
+	if !startsWithPackageClause(text) {
+
+		fragmentFset := token.NewFileSet()
+		fragment, fragmentErr := parser.ParseFile(fragmentFset, "", fragmentPrefix+text+"\n}", parser.ParseComments)
+
+		if fragmentErr == nil {
+			return &SourceFile{text: text, fset: fragmentFset, file: fragment, offset: len(fragmentPrefix)}, nil
+		}
+	}
+
+	// Return whatever could be parsed
+	if file == nil {
+		file = &ast.File{Name: ast.NewIdent("")}
+	}
+
+	return &SourceFile{text: text, fset: fset, file: file}, err
+}
+
+// Returns true if the first token in the text is the "package" keyword
+func startsWithPackageClause(text string) bool {
+
+	var s scanner.Scanner
+	fset := token.NewFileSet()
+	s.Init(fset.AddFile("", fset.Base(), len(text)), []byte(text), nil, 0)
+
+	_, tok, _ := s.Scan()
+
+	return tok == token.PACKAGE
+}
+
+// Returns the original source text
+func (s *SourceFile) Text() string {
+	return s.text
+}
+
+// Returns the syntax tree for the source
+func (s *SourceFile) File() *ast.File {
+	return s.file
+}
+
+// Returns the file set used to parse the source
+func (s *SourceFile) FileSet() *token.FileSet {
+	return s.fset
+}
+
+// Converts a syntax tree position into an index into the original text
+func (s *SourceFile) index(pos token.Pos) int {
+
+	i := s.fset.Position(pos).Offset - s.offset
+
+	if i < 0 {
+		return 0
+	}
+
+	if i > len(s.text) {
+		return len(s.text)
+	}
+
+	return i
+}
+
+// Returns the original text between the specified positions with all comments removed
+func (s *SourceFile) textWithoutComments(start token.Pos, end token.Pos) string {
+
+	from := s.index(start)
+	to := s.index(end)
+
+	var sb strings.Builder
+
+	for _, group := range s.file.Comments {
+		for _, comment := range group.List {
+
+			commentStart := s.index(comment.Pos())
+			commentEnd := s.index(comment.End())
+
+			if commentEnd <= from || commentStart >= to {
+				continue
+			}
+
+			if commentStart > from {
+				sb.WriteString(s.text[from:commentStart])
+			}
+
+			if commentEnd > from {
+				from = commentEnd
+			}
+		}
+	}
+
+	if from < to {
+		sb.WriteString(s.text[from:to])
+	}
+
+	return sb.String()
+}
+
+// Returns the function (or method) declaration with the specified name.
+// Returns nil if the declaration can't be found.
+func (s *SourceFile) FuncDecl(functionName string) *ast.FuncDecl {
+
+	var found *ast.FuncDecl
+
+	ast.Inspect(s.file, func(n ast.Node) bool {
+
+		if found != nil {
+			return false
+		}
+
+		if decl, ok := n.(*ast.FuncDecl); ok && decl.Name.Name == functionName && decl.Body != nil {
+			found = decl
+		}
+
+		return true
+	})
+
+	return found
+}
+
+// Returns the specified function body text with comments removed.
+// First return value = true if function was found, otherwise false
+// Second return value = function body text (excluding the enclosing curly braces)
+func (s *SourceFile) FunctionBody(functionName string) (bool, string) {
+
+	decl := s.FuncDecl(functionName)
+
+	if decl == nil {
+		return false, ""
+	}
+
+	return true, s.textWithoutComments(decl.Body.Lbrace+1, decl.Body.Rbrace)
+}
+
+// Returns the number of variables of the specified type declared in the node.
+// Counts "var x Type" declarations along with variables initialized with a
+// "Type{...}" composite literal (e.g., "x := Type{}" or "var x = Type{}")
+func countInstantiations(node ast.Node, objectName string) int {
+
+	count := 0
+
+	ast.Inspect(node, func(n ast.Node) bool {
+
+		switch n := n.(type) {
+
+		case *ast.ValueSpec:
+
+			if n.Type != nil {
+				if types.ExprString(n.Type) == objectName {
+					count += len(n.Names)
+				}
+			} else {
+				for _, value := range n.Values {
+					if isCompositeLitOf(value, objectName) {
+						count++
+					}
+				}
+			}
+
+		case *ast.AssignStmt:
+
+			if n.Tok == token.DEFINE {
+				for _, value := range n.Rhs {
+					if isCompositeLitOf(value, objectName) {
+						count++
+					}
+				}
+			}
+		}
+
+		return true
+	})
+
+	return count
+}
+
+// Returns true if the expression is a composite literal of the specified type
+func isCompositeLitOf(expr ast.Expr, objectName string) bool {
+
+	lit, ok := expr.(*ast.CompositeLit)
+
+	return ok && lit.Type != nil && types.ExprString(lit.Type) == objectName
+}
+
+// Returns the number of variables of the specified type instantiated in the source.
+// If functionName isn't empty and the function is declared in the source, only
+// variables instantiated in that function are counted.
+func (s *SourceFile) CountInstantiations(objectName string, functionName string) int {
+
+	if functionName != "" {
+		if decl := s.FuncDecl(functionName); decl != nil {
+			return countInstantiations(decl.Body, objectName)
+		}
+	}
+
+	return countInstantiations(s.file, objectName)
+}
+
+// Returns all calls to the specified package level function (e.g., "rand", "Seed")
+func (s *SourceFile) PackageCalls(packageName string, functionName string) []*ast.CallExpr {
+
+	var calls []*ast.CallExpr
+
+	ast.Inspect(s.file, func(n ast.Node) bool {
+
+		if call, ok := n.(*ast.CallExpr); ok {
+			if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == functionName {
+				if ident, ok := sel.X.(*ast.Ident); ok && ident.Name == packageName {
+					calls = append(calls, call)
+				}
+			}
+		}
+
+		return true
+	})
+
+	return calls
+}
+
+// Returns true if the source maps the specified command line flag name
+// using the specified "flag" package function (e.g., "IntVar")
+func (s *SourceFile) HasFlagVar(functionName string, flagName string) bool {
+
+	for _, call := range s.PackageCalls("flag", functionName) {
+
+		if len(call.Args) < 2 {
+			continue
+		}
+
+		if lit, ok := call.Args[1].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+			if name, err := strconv.Unquote(lit.Value); err == nil && name == flagName {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Returns true if the source contains the standard random number
+// seeding template:
+//
+//	func init() {
+//		rand.Seed(int64(time.Now().Nanosecond()))
+//	}
+func (s *SourceFile) HasRandomSeedTemplate() bool {
+
+	for _, decl := range s.file.Decls {
+
+		funcDecl, ok := decl.(*ast.FuncDecl)
+
+		if !ok || funcDecl.Recv != nil || funcDecl.Name.Name != "init" || funcDecl.Body == nil {
+			continue
+		}
+
+		if funcDecl.Type.Params.NumFields() != 0 || len(funcDecl.Body.List) != 1 {
+			continue
+		}
+
+		if stmt, ok := funcDecl.Body.List[0].(*ast.ExprStmt); ok {
+			if types.ExprString(stmt.X) == "rand.Seed(int64(time.Now().Nanosecond()))" {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Removes all comments from the specified text using the Go tokenizer
+// so comment markers inside string literals are left alone
+func removeComments(text string) string {
+
+	var s scanner.Scanner
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(text))
+
+	// Ignore errors so text that isn't valid Go is still processed
+	s.Init(file, []byte(text), func(token.Position, string) {}, scanner.ScanComments)
+
+	var sb strings.Builder
+	from := 0
+
+	for {
+		pos, tok, _ := s.Scan()
+
+		if tok == token.EOF {
+			break
+		}
+
+		if tok != token.COMMENT {
+			continue
+		}
+
+		start := file.Offset(pos)
+		end := len(text)
+
+		// Find the end of the comment in the original text
+		// (the scanner strips carriage returns from comment literals)
+		if strings.HasPrefix(text[start:], "/*") {
+			if i := strings.Index(text[start+2:], "*/"); i >= 0 {
+				end = start + 2 + i + 2
+			}
+		} else if i := strings.IndexByte(text[start:], '\n'); i >= 0 {
+			end = start + i
+			if end > start && text[end-1] == '\r' {
+				end--
+			}
+		}
+
+		sb.WriteString(text[from:start])
+		from = end
+	}
+
+	sb.WriteString(text[from:])
+
+	return sb.String()
+}
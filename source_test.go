@@ -0,0 +1,183 @@
+package helpers
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGetFunctionBodyText(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		text     string
+		function string
+		found    bool
+		body     string
+	}{
+		{
+			name:     "simple body",
+			text:     "package main\nfunc add(a int, b int) int {\n\treturn a + b\n}\n",
+			function: "add",
+			found:    true,
+			body:     "\n\treturn a + b\n",
+		},
+		{
+			name:     "braces inside strings",
+			text:     "package main\nfunc show() {\n\tfmt.Println(\"}{\", '}')\n}\nfunc other() {}\n",
+			function: "show",
+			found:    true,
+			body:     "\n\tfmt.Println(\"}{\", '}')\n",
+		},
+		{
+			name:     "comment markers inside strings",
+			text:     "package main\nfunc url() string {\n\treturn \"http://example.com\" // home page\n}\n",
+			function: "url",
+			found:    true,
+			body:     "\n\treturn \"http://example.com\" \n",
+		},
+		{
+			name:     "comment markers inside raw strings",
+			text:     "package main\nfunc raw() string {\n\t/* note */ return `// not a comment }`\n}\n",
+			function: "raw",
+			found:    true,
+			body:     "\n\t return `// not a comment }`\n",
+		},
+		{
+			name:     "method",
+			text:     "package main\ntype T struct{}\nfunc (t T) Name() string {\n\treturn \"T\"\n}\n",
+			function: "Name",
+			found:    true,
+			body:     "\n\treturn \"T\"\n",
+		},
+		{
+			name:     "nested function literal",
+			text:     "package main\nfunc outer() {\n\tf := func() { inner() }\n\tf()\n}\n",
+			function: "outer",
+			found:    true,
+			body:     "\n\tf := func() { inner() }\n\tf()\n",
+		},
+		{
+			name:     "missing function",
+			text:     "package main\nfunc add(a int, b int) int {\n\treturn a + b\n}\n",
+			function: "subtract",
+		},
+		{
+			name:     "name only in a string",
+			text:     "package main\nfunc main() {\n\tfmt.Println(\"func helper() {}\")\n}\n",
+			function: "helper",
+		},
+	}
+
+	for _, test := range tests {
+
+		found, body := GetFunctionBodyText(test.text, test.function)
+
+		if found != test.found || body != test.body {
+			t.Errorf("%s: got (%v, %q), expected (%v, %q)", test.name, found, body, test.found, test.body)
+		}
+	}
+}
+
+func TestParseSource(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		text    string
+		invalid bool
+		calls   int // calls to fmt.Println
+	}{
+		{name: "complete file", text: "package main\nimport \"fmt\"\nfunc main() {\n\tfmt.Println(\"hi\")\n}\n", calls: 1},
+		{name: "function body", text: "x := 1\nfmt.Println(x)\nfmt.Println(\"fmt.Println(x)\")\n", calls: 2},
+		{name: "comment", text: "// fmt.Println(x)\nfmt.Println(1)\n", calls: 1},
+		{name: "invalid file", text: "package main\nfunc main() {\n\tfmt.Println(\"hi\"\n}\n", invalid: true},
+		{name: "invalid body", text: "fmt.Println(\"hi\"", invalid: true},
+	}
+
+	for _, test := range tests {
+
+		source, err := ParseSource(test.text)
+
+		if (err != nil) != test.invalid {
+			t.Errorf("%s: got error %v, expected invalid = %v", test.name, err, test.invalid)
+		}
+
+		if source == nil {
+			t.Errorf("%s: no source returned", test.name)
+			continue
+		}
+
+		if source.Text() != test.text {
+			t.Errorf("%s: Text() = %q, expected the original text", test.name, source.Text())
+		}
+
+		if !test.invalid {
+			if calls := len(source.PackageCalls("fmt", "Println")); calls != test.calls {
+				t.Errorf("%s: found %d calls to fmt.Println, expected %d", test.name, calls, test.calls)
+			}
+		}
+	}
+}
+
+func TestRemoveAllComments(t *testing.T) {
+
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{text: "x := 1 // one", expected: "x := 1 "},
+		{text: "x := \"a // b\" // c", expected: "x := \"a // b\" "},
+		{text: "x := `/* a */` /* b */ + 1", expected: "x := `/* a */`  + 1"},
+		{text: "a /* multi\nline */ b", expected: "a  b"},
+		{text: "x := 1 // one\r\ny := 2", expected: "x := 1 \r\ny := 2"},
+		{text: "not { valid // go", expected: "not { valid "},
+	}
+
+	for _, test := range tests {
+
+		if actual := RemoveAllComments(test.text); actual != test.expected {
+			t.Errorf("RemoveAllComments(%q) = %q, expected %q", test.text, actual, test.expected)
+		}
+	}
+}
+
+func TestSourceChecks(t *testing.T) {
+
+	text := strings.Join([]string{
+		"package main",
+		"func init() {",
+		"\trand.Seed(int64(time.Now().Nanosecond()))",
+		"}",
+		"func main() {",
+		"\tvar a Account",
+		"\tb := Account{}",
+		"\tname := \"Account{}\"",
+		"\tflag.IntVar(&n, \"n\", 1, \"flag.IntVar(&m, \\\"m\\\", 1, \\\"\\\")\")",
+		"}",
+		"func other() {",
+		"\tc, d := Account{}, Account{}",
+		"}",
+	}, "\n")
+
+	source, err := ParseSource(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		actual   interface{}
+		expected interface{}
+	}{
+		{name: "instantiations in main", actual: source.CountInstantiations("Account", "main"), expected: 2},
+		{name: "instantiations in the file", actual: source.CountInstantiations("Account", ""), expected: 4},
+		{name: "IntVar flag", actual: source.HasFlagVar("IntVar", "n"), expected: true},
+		{name: "flag inside a string", actual: source.HasFlagVar("IntVar", "m"), expected: false},
+		{name: "random seed template", actual: source.HasRandomSeedTemplate(), expected: true},
+	}
+
+	for _, test := range tests {
+		if test.actual != test.expected {
+			t.Errorf("%s: got %v, expected %v", test.name, test.actual, test.expected)
+		}
+	}
+}
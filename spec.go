@@ -0,0 +1,348 @@
+package helpers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// Assignment spec describing the output tests for an assignment.
+// Specs are written in JSON, for example:
+//
+//	{
+//	  "functions": [
+//	    {"name": "Sum", "cases": [
+//	      {"args": [[1, 2, 3]], "returns": [6], "points": 2},
+//	      {"args": [[]], "returns": [0], "stdout": ["No numbers"], "hide_expected": true}
+//	    ]}
+//	  ],
+//	  "methods": [
+//	    {"type": "Account", "name": "Deposit", "cases": [
+//	      {"args": [25.5], "returns": [25.5], "tolerance": 0.001}
+//	    ]}
+//	  ]
+//	}
+//
+// Argument and return values are converted to the parameter/return types of
+// the signature registered for the function or method (see Registry), so a
+// submission declared with a different signature fails the anatomy check.
+type Spec struct {
+	Functions []FunctionSpec `json:"functions"`
+	Methods   []MethodSpec   `json:"methods"`
+}
+
+// Output test cases for a function
+type FunctionSpec struct {
+	Name  string     `json:"name"`
+	Cases []CaseSpec `json:"cases"`
+}
+
+// Output test cases for a method of a registered type
+type MethodSpec struct {
+	Type  string     `json:"type"`
+	Name  string     `json:"name"`
+	Cases []CaseSpec `json:"cases"`
+}
+
+// A single output test case.
+// The final argument of a variadic function is a list holding all of the variadic arguments.
+// Timeout uses time.ParseDuration's format (e.g., "500ms").
+// Tolerance = how close floating point return values need to be (0 = exact)
+// ExpectExit/ExitCode = the call should end the program with the exit status (see FuncOutputTest)
+// Ungraded = the case is reported but worth no points (see FuncOutputTest)
+type CaseSpec struct {
+	Args          []json.RawMessage `json:"args"`
+	Stdin         []string          `json:"stdin"`
+	IgnoreStdout  bool              `json:"ignore_stdout"`
+	Stdout        []string          `json:"stdout"`
+	IgnoreStderr  bool              `json:"ignore_stderr"`
+	Stderr        []string          `json:"stderr"`
+	HideExpected  bool              `json:"hide_expected"`
+	IgnoreReturns bool              `json:"ignore_returns"`
+	Returns       []json.RawMessage `json:"returns"`
+	Tolerance     float64           `json:"tolerance"`
+	Timeout       string            `json:"timeout"`
+	Isolate       bool              `json:"isolate"`
+	ExpectExit    bool              `json:"expect_exit"`
+	ExitCode      int               `json:"exit_code"`
+	Points        float64           `json:"points"`
+	Ungraded      bool              `json:"ungraded"`
+	Category      string            `json:"category"`
+}
+
+// Reads the assignment spec from the specified file
+func LoadSpec(fileName string) (*Spec, error) {
+
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	spec, err := ParseSpec(data)
+	if err != nil {
+		return nil, errors.New(fileName + ": " + err.Error())
+	}
+
+	return spec, nil
+}
+
+// Parses the JSON assignment spec.
+// Unknown fields are reported as errors so typos don't silently drop checks.
+func ParseSpec(data []byte) (*Spec, error) {
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	var spec Spec
+	if err := decoder.Decode(&spec); err != nil {
+		return nil, err
+	}
+
+	return &spec, nil
+}
+
+// Functions and methods that specs can refer to by name, along with the
+// signatures the assignment requires them to have
+type Registry struct {
+	functions map[string]registeredFunction
+	methods   map[string]reflect.Type // by "Type.Method"
+}
+
+// Registered function and its required signature
+type registeredFunction struct {
+	function  interface{}
+	signature reflect.Type
+}
+
+// Creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{functions: map[string]registeredFunction{}, methods: map[string]reflect.Type{}}
+}
+
+// Returns the function type F (e.g., FuncType[func(...float64) float64]())
+func FuncType[F any]() reflect.Type {
+
+	functionType := reflect.TypeOf((*F)(nil)).Elem()
+
+	if functionType.Kind() != reflect.Func {
+		panic("FuncType requires a function type, found " + functionType.String())
+	}
+
+	return functionType
+}
+
+// Registers the function under the specified name along with the signature
+// it must have (e.g., r.Function("Sum", Sum, FuncType[func(...int) int]()))
+func (r *Registry) Function(name string, function interface{}, signature reflect.Type) *Registry {
+
+	if signature == nil || signature.Kind() != reflect.Func {
+		panic("Registered function '" + name + "' needs a function signature (see FuncType)")
+	}
+
+	r.functions[name] = registeredFunction{function: function, signature: signature}
+	return r
+}
+
+// Registers the signature a method of the specified type must have, not including
+// the receiver (e.g., r.Method("Account", "Deposit", FuncType[func(float64) float64]()))
+func (r *Registry) Method(typeName string, name string, signature reflect.Type) *Registry {
+
+	if signature == nil || signature.Kind() != reflect.Func {
+		panic("Registered method '" + typeName + "." + name + "' needs a function signature (see FuncType)")
+	}
+
+	r.methods[typeName+"."+name] = signature
+	return r
+}
+
+// Builds the function output tests described by the spec.
+// Returns an error if the spec doesn't match the registry (a function that
+// isn't registered or a value that can't be converted to the registered types).
+func (s *Spec) FunctionTests(r *Registry) ([]FuncOutputTest, error) {
+
+	var tests []FuncOutputTest
+
+	for _, fs := range s.Functions {
+
+		registered, ok := r.functions[fs.Name]
+		if !ok {
+			return nil, errors.New("Function '" + fs.Name + "' is not registered")
+		}
+
+		for i, cs := range fs.Cases {
+
+			description := "Function '" + fs.Name + "' case " + strconv.Itoa(i+1)
+
+			c, err := cs.convert(description, registered.signature)
+			if err != nil {
+				return nil, err
+			}
+
+			tests = append(tests, FuncOutputTest{
+				Name:              fs.Name,
+				Obj:               registered.function,
+				Args:              c.args,
+				Variadic:          registered.signature.IsVariadic(),
+				StdinStrings:      cs.Stdin,
+				IgnoreStdout:      cs.IgnoreStdout,
+				StdoutStrings:     cs.Stdout,
+				IgnoreStderr:      cs.IgnoreStderr,
+				StderrStrings:     cs.Stderr,
+				HideExpected:      cs.HideExpected,
+				IgnoreReturns:     cs.IgnoreReturns,
+				Returns:           c.returns,
+				ReturnComparators: c.comparators,
+				Timeout:           c.timeout,
+				Isolate:           cs.Isolate,
+				ExpectExit:        cs.ExpectExit,
+				ExitCode:          cs.ExitCode,
+				Points:            cs.Points,
+				Ungraded:          cs.Ungraded,
+				Category:          cs.Category,
+			})
+		}
+	}
+
+	return tests, nil
+}
+
+// Builds the method output tests the spec describes for the specified type.
+// The tests are meant to be run with RunMethodOutputTests using an object of that type.
+// Returns an error if the spec doesn't match the registry (see FunctionTests).
+func (s *Spec) MethodTests(r *Registry, typeName string) ([]MethodOutputTest, error) {
+
+	var tests []MethodOutputTest
+
+	for _, ms := range s.Methods {
+
+		if ms.Type != typeName {
+			continue
+		}
+
+		signature, ok := r.methods[typeName+"."+ms.Name]
+		if !ok {
+			return nil, errors.New("Method '" + typeName + "." + ms.Name + "' is not registered")
+		}
+
+		for i, cs := range ms.Cases {
+
+			description := typeName + " method '" + ms.Name + "' case " + strconv.Itoa(i+1)
+
+			c, err := cs.convert(description, signature)
+			if err != nil {
+				return nil, err
+			}
+
+			tests = append(tests, MethodOutputTest{
+				Name:              ms.Name,
+				Args:              c.args,
+				Variadic:          signature.IsVariadic(),
+				StdinStrings:      cs.Stdin,
+				IgnoreStdout:      cs.IgnoreStdout,
+				StdoutStrings:     cs.Stdout,
+				IgnoreStderr:      cs.IgnoreStderr,
+				StderrStrings:     cs.Stderr,
+				HideExpected:      cs.HideExpected,
+				IgnoreReturns:     cs.IgnoreReturns,
+				Returns:           c.returns,
+				ReturnComparators: c.comparators,
+				Timeout:           c.timeout,
+				Isolate:           cs.Isolate,
+				ExpectExit:        cs.ExpectExit,
+				ExitCode:          cs.ExitCode,
+				Points:            cs.Points,
+				Ungraded:          cs.Ungraded,
+				Category:          cs.Category,
+			})
+		}
+	}
+
+	return tests, nil
+}
+
+// Case values converted for a specific function type
+type convertedCase struct {
+	args        []reflect.Value
+	returns     []reflect.Value
+	comparators []Comparator
+	timeout     time.Duration
+}
+
+// Converts the case's literal values to the signature's parameter and return types
+func (cs CaseSpec) convert(description string, functionType reflect.Type) (convertedCase, error) {
+
+	var c convertedCase
+
+	if len(cs.Args) != functionType.NumIn() {
+		return c, errors.New(description + " has " + strconv.Itoa(len(cs.Args)) + " argument(s), expected " + strconv.Itoa(functionType.NumIn()))
+	}
+
+	for i, arg := range cs.Args {
+
+		value, err := convertLiteral(arg, functionType.In(i))
+		if err != nil {
+			return c, errors.New(description + " argument " + strconv.Itoa(i+1) + ": " + err.Error())
+		}
+
+		c.args = append(c.args, value)
+	}
+
+	if cs.IgnoreReturns && len(cs.Returns) == 0 {
+
+		// Returns are still needed for the anatomy check
+		for i := 0; i < functionType.NumOut(); i++ {
+			c.returns = append(c.returns, reflect.Zero(functionType.Out(i)))
+		}
+
+	} else {
+
+		if len(cs.Returns) != functionType.NumOut() {
+			return c, errors.New(description + " has " + strconv.Itoa(len(cs.Returns)) + " return value(s), expected " + strconv.Itoa(functionType.NumOut()))
+		}
+
+		for i, ret := range cs.Returns {
+
+			value, err := convertLiteral(ret, functionType.Out(i))
+			if err != nil {
+				return c, errors.New(description + " return value " + strconv.Itoa(i+1) + ": " + err.Error())
+			}
+
+			c.returns = append(c.returns, value)
+		}
+
+		if cs.Tolerance > 0 {
+			for range c.returns {
+				c.comparators = append(c.comparators, Equal(FloatTolerance(cs.Tolerance)))
+			}
+		}
+	}
+
+	if cs.Timeout != "" {
+
+		timeout, err := time.ParseDuration(cs.Timeout)
+		if err != nil {
+			return c, errors.New(description + " timeout: " + err.Error())
+		}
+
+		c.timeout = timeout
+	}
+
+	return c, nil
+}
+
+// Converts a JSON literal to a value of the specified type
+// (e.g., 3 to an int, [1, 2] to a []float64 or {"X": 1} to a struct)
+func convertLiteral(literal json.RawMessage, t reflect.Type) (reflect.Value, error) {
+
+	value := reflect.New(t)
+
+	if err := json.Unmarshal(literal, value.Interface()); err != nil {
+		return reflect.Value{}, errors.New("unable to convert " + string(literal) + " to " + t.String() + " (" + err.Error() + ")")
+	}
+
+	return value.Elem(), nil
+}
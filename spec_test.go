@@ -0,0 +1,241 @@
+package helpers
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Submission functions with the wrong signature for the specs below
+func averageReturningInt(nums ...float64) int {
+	return 0
+}
+
+func sumWithExtraParameter(nums []int, start int) int {
+	return start
+}
+
+func sumOfSlice(nums []int) int {
+
+	total := 0
+	for _, n := range nums {
+		total += n
+	}
+
+	return total
+}
+
+func TestCaseSpecConvert(t *testing.T) {
+
+	raw := func(literals ...string) []json.RawMessage {
+		var messages []json.RawMessage
+		for _, literal := range literals {
+			messages = append(messages, json.RawMessage(literal))
+		}
+		return messages
+	}
+
+	tests := []struct {
+		name      string
+		cs        CaseSpec
+		signature reflect.Type
+		args      []interface{}
+		returns   []interface{}
+		err       string
+	}{
+		{
+			name:      "converts to parameter types",
+			cs:        CaseSpec{Args: raw(`3`, `"x"`), Returns: raw(`2`)},
+			signature: FuncType[func(float64, string) int](),
+			args:      []interface{}{3.0, "x"},
+			returns:   []interface{}{2},
+		},
+		{
+			name:      "variadic arguments are a list",
+			cs:        CaseSpec{Args: raw(`[1, 2, 3]`), Returns: raw(`2`)},
+			signature: FuncType[func(...float64) float64](),
+			args:      []interface{}{[]float64{1, 2, 3}},
+			returns:   []interface{}{2.0},
+		},
+		{
+			name:      "ignored returns use zero values",
+			cs:        CaseSpec{Args: raw(`1`), IgnoreReturns: true},
+			signature: FuncType[func(int) (string, error)](),
+			args:      []interface{}{1},
+			returns:   []interface{}{"", error(nil)},
+		},
+		{
+			name:      "wrong number of arguments",
+			cs:        CaseSpec{Args: raw(`1`, `2`)},
+			signature: FuncType[func(int) int](),
+			err:       "has 2 argument(s), expected 1",
+		},
+		{
+			name:      "wrong number of returns",
+			cs:        CaseSpec{Args: raw(`1`)},
+			signature: FuncType[func(int) int](),
+			err:       "has 0 return value(s), expected 1",
+		},
+		{
+			name:      "literal of the wrong type",
+			cs:        CaseSpec{Args: raw(`"x"`), Returns: raw(`1`)},
+			signature: FuncType[func(int) int](),
+			err:       "argument 1: unable to convert \"x\" to int",
+		},
+		{
+			name:      "invalid timeout",
+			cs:        CaseSpec{Args: raw(`1`), Returns: raw(`1`), Timeout: "soon"},
+			signature: FuncType[func(int) int](),
+			err:       "timeout:",
+		},
+	}
+
+	for _, test := range tests {
+
+		c, err := test.cs.convert("case", test.signature)
+
+		if test.err != "" {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("%s: expected error containing %q, got %v", test.name, test.err, err)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", test.name, err)
+			continue
+		}
+
+		if !valuesEqual(c.args, test.args) {
+			t.Errorf("%s: args = %v, expected %v", test.name, c.args, test.args)
+		}
+
+		if !valuesEqual(c.returns, test.returns) {
+			t.Errorf("%s: returns = %v, expected %v", test.name, c.returns, test.returns)
+		}
+
+		for i, ret := range c.returns {
+			if ret.Type() != test.signature.Out(i) {
+				t.Errorf("%s: return %d has type %s, expected %s", test.name, i, ret.Type(), test.signature.Out(i))
+			}
+		}
+	}
+}
+
+func TestCaseSpecConvertOptions(t *testing.T) {
+
+	cs := CaseSpec{
+		Args:      []json.RawMessage{json.RawMessage(`1`)},
+		Returns:   []json.RawMessage{json.RawMessage(`1.5`)},
+		Tolerance: 0.01,
+		Timeout:   "250ms",
+	}
+
+	c, err := cs.convert("case", FuncType[func(int) float64]())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(c.comparators) != 1 || !c.comparators[0](reflect.ValueOf(1.5), reflect.ValueOf(1.505)) {
+		t.Errorf("expected a float tolerance comparator, got %v", c.comparators)
+	}
+
+	if c.timeout != 250*time.Millisecond {
+		t.Errorf("timeout = %v, expected 250ms", c.timeout)
+	}
+}
+
+func TestSpecChecksSubmissionAgainstRegisteredSignature(t *testing.T) {
+
+	spec, err := ParseSpec([]byte(`{"functions": [
+		{"name": "Average", "cases": [{"args": [[1, 2, 3]], "returns": [2]}]},
+		{"name": "Sum", "cases": [{"args": [[1, 2]], "returns": [3]}]},
+		{"name": "Total", "cases": [{"args": [[1, 2]], "returns": [3]}]}
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	registry := NewRegistry().
+		Function("Average", averageReturningInt, FuncType[func(...float64) float64]()).
+		Function("Sum", sumWithExtraParameter, FuncType[func([]int) int]()).
+		Function("Total", sumOfSlice, FuncType[func([]int) int]())
+
+	// One submission function with the wrong signature doesn't stop the others from being tested
+	tests, err := spec.FunctionTests(registry)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(tests) != 3 {
+		t.Fatalf("expected 3 tests, got %d", len(tests))
+	}
+
+	expected := map[string]string{
+		"Average": "returned unexpected data type. Expected type float64, received type int",
+		"Sum":     "has unexpected number of parameters",
+		"Total":   "",
+	}
+
+	for _, test := range tests {
+
+		messages := checkFunctionAnatomy(convertFuncOutputTestToAnatomyTest(test))
+
+		if expected[test.Name] == "" {
+			if len(messages) > 0 {
+				t.Errorf("%s: unexpected anatomy failure %v", test.Name, messages)
+			}
+		} else if len(messages) == 0 || !strings.Contains(messages[0], expected[test.Name]) {
+			t.Errorf("%s: expected anatomy failure containing %q, got %v", test.Name, expected[test.Name], messages)
+		}
+	}
+}
+
+func TestSpecMethodTestsUseRegisteredSignature(t *testing.T) {
+
+	spec, err := ParseSpec([]byte(`{"methods": [
+		{"type": "Account", "name": "Deposit", "cases": [{"args": [25], "returns": [25]}]}
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := spec.MethodTests(NewRegistry(), "Account"); err == nil {
+		t.Error("expected an error for a method that isn't registered")
+	}
+
+	tests, err := spec.MethodTests(NewRegistry().Method("Account", "Deposit", FuncType[func(float64) float64]()), "Account")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(tests) != 1 || tests[0].Args[0].Type() != reflect.TypeOf(0.0) || tests[0].Returns[0].Type() != reflect.TypeOf(0.0) {
+		t.Errorf("expected float64 argument and return, got %v", tests)
+	}
+}
+
+// Returns true if the values are the same as the expected values (including their types)
+func valuesEqual(values []reflect.Value, expected []interface{}) bool {
+
+	if len(values) != len(expected) {
+		return false
+	}
+
+	for i, v := range values {
+
+		if expected[i] == nil {
+			if !v.IsZero() {
+				return false
+			}
+			continue
+		}
+
+		if !reflect.DeepEqual(v.Interface(), expected[i]) {
+			return false
+		}
+	}
+
+	return true
+}
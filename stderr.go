@@ -0,0 +1,102 @@
+package helpers
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"os"
+	"strings"
+)
+
+// When true, output tests that don't set StderrStrings or StderrMatchers expect
+// nothing to be written to stderr (unless IgnoreStderr is set)
+var CheckStderr = false
+
+// Captures everything written to stderr while a function runs
+// (see redirectStderr for what's captured)
+type stderrCapture struct {
+	writer  *os.File
+	restore func()
+	data    bytes.Buffer
+	done    chan struct{}
+	stopped bool
+	lines   []string // lines written before the capture stopped
+}
+
+// Starts capturing stderr.
+// Returns nil if stderr can't be redirected (the stderr check will then see no output).
+func captureStderr() *stderrCapture {
+
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return nil
+	}
+
+	s := &stderrCapture{
+		writer:  writer,
+		restore: redirectStderr(writer),
+		done:    make(chan struct{}),
+	}
+
+	go func() {
+		io.Copy(&s.data, reader)
+		reader.Close()
+		close(s.done)
+	}()
+
+	return s
+}
+
+// Sends everything written to stderr to w: os.Stderr, the standard logger
+// (without its date/time prefix) and, where supported, anything written
+// directly to file descriptor 2 (e.g., println).
+// Returns a function that undoes the redirection.
+func redirectStderr(w *os.File) func() {
+
+	oldStderr := os.Stderr
+	oldLog := log.Writer()
+	oldFlags := log.Flags()
+
+	//TODO: handle errors, maybe? (os.Stderr and the log package are still redirected)
+	restoreFd, _ := redirectFd(2, w)
+
+	os.Stderr = w
+	log.SetOutput(w)
+	log.SetFlags(0)
+
+	return func() {
+
+		if restoreFd != nil {
+			restoreFd()
+		}
+
+		os.Stderr = oldStderr
+		log.SetOutput(oldLog)
+		log.SetFlags(oldFlags)
+	}
+}
+
+// Stops capturing stderr and returns the lines that were written
+// (calling it again returns the same lines)
+func (s *stderrCapture) stop() []string {
+
+	if s == nil {
+		return nil
+	}
+
+	if s.stopped {
+		return s.lines
+	}
+
+	s.stopped = true
+	s.restore()
+
+	s.writer.Close()
+	<-s.done
+
+	if text := strings.TrimSuffix(s.data.String(), "\n"); text != "" {
+		s.lines = strings.Split(text, "\n")
+	}
+
+	return s.lines
+}
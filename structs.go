@@ -0,0 +1,198 @@
+package helpers
+
+import (
+	"go/token"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// Expected struct field.
+// The field must be exported if Name starts with an upper case letter.
+// Type = expected type (nil = not checked)
+// Embedded = true if the field should be embedded (e.g., "sync.Mutex" instead of "mu sync.Mutex").
+// The Name of an embedded field is its type's name (e.g., "Mutex").
+// Tags = expected struct tag values by key (e.g., {"json": "name,omitempty"})
+type FieldAnatomy struct {
+	Name     string
+	Type     reflect.Type
+	Embedded bool
+	Tags     map[string]string
+}
+
+// Struct anatomy testing struct
+// Name = name of the struct (used in messages)
+// Obj = value of the struct type (or a pointer to one, e.g., &Account{})
+// AllowExtraFields = true if the struct may declare fields that aren't listed in Fields
+// Points/Category = weight and category used for scoring (see Scorecard; 0 Points = DefaultPoints)
+// Ungraded = true if the check is reported but worth no points (Points is ignored)
+type StructAnatomyTest struct {
+	Name             string
+	Obj              interface{}
+	Fields           []FieldAnatomy
+	AllowExtraFields bool
+	Points           float64
+	Ungraded         bool
+	Category         string
+}
+
+// Runs struct field anatomy tests
+func RunStructAnatomyTests(tests []StructAnatomyTest, t *testing.T) {
+	RunStructAnatomyTestsWithMode(tests, DefaultFailureMode, t)
+}
+
+// Runs struct field anatomy tests using the specified failure mode
+func RunStructAnatomyTestsWithMode(tests []StructAnatomyTest, mode FailureMode, t *testing.T) {
+
+	// If a test failure has already occurred, no need to report further problems.
+	// The tests are still run so they can be scored.
+	report := !t.Failed()
+
+	for _, test := range tests {
+		runCase(t, mode, test.Name, report, func(t *testing.T) ScoreEntry {
+			return newScoreEntry(test.Name, test.Category, "Anatomy", test.Points, test.Ungraded, checkStructAnatomy(test))
+		})
+	}
+}
+
+// Runs the struct anatomy test.
+// Returns an error message for each problem found.
+func checkStructAnatomy(test StructAnatomyTest) []string {
+
+	structType := reflect.TypeOf(test.Obj)
+	if structType != nil && structType.Kind() == reflect.Pointer {
+		structType = structType.Elem()
+	}
+
+	if structType == nil || structType.Kind() != reflect.Struct {
+		return []string{"'" + test.Name + "' struct definition missing."}
+	}
+
+	var messages []string
+	matched := map[string]bool{}
+
+	for _, expected := range test.Fields {
+
+		field, found := structType.FieldByName(expected.Name)
+
+		// FieldByName also finds the fields of embedded structs
+		if found && len(field.Index) > 1 {
+			found = false
+		}
+
+		if !found {
+
+			if similar, ok := similarField(structType, expected.Name); ok {
+				matched[similar.Name] = true
+				messages = append(messages, similarFieldMessage(test.Name, expected.Name, similar.Name))
+			} else {
+				messages = append(messages, test.Name+" struct definition missing '"+expected.Name+"' field")
+			}
+
+			continue
+		}
+
+		matched[field.Name] = true
+		messages = append(messages, checkField(test.Name, expected, field)...)
+	}
+
+	if !test.AllowExtraFields {
+
+		var extras []string
+
+		for i := 0; i < structType.NumField(); i++ {
+			if name := structType.Field(i).Name; !matched[name] {
+				extras = append(extras, "'"+name+"'")
+			}
+		}
+
+		if len(extras) > 0 {
+			messages = append(messages, test.Name+" struct has unexpected field(s): "+strings.Join(extras, ", ")+
+				". Only the fields required by the assignment should be declared.")
+		}
+	}
+
+	return messages
+}
+
+// Compares a field to the expected field.
+// Returns an error message for each problem found.
+func checkField(structName string, expected FieldAnatomy, field reflect.StructField) []string {
+
+	var messages []string
+	description := structName + " field '" + field.Name + "'"
+
+	if expected.Type != nil && field.Type != expected.Type {
+		messages = append(messages, description+" has unexpected data type. Expected type "+
+			expected.Type.String()+", found type "+field.Type.String())
+	}
+
+	if field.Anonymous != expected.Embedded {
+
+		if expected.Embedded {
+			messages = append(messages, description+" must be embedded (declared using only its type, e.g., \""+embeddedExample(expected, field)+"\")")
+		} else {
+			messages = append(messages, description+" must not be embedded (declare it with a name, e.g., \""+field.Name+" "+field.Type.String()+"\")")
+		}
+	}
+
+	var keys []string
+	for key := range expected.Tags {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+
+		value, ok := field.Tag.Lookup(key)
+		expectedTag := key + ":" + strconv.Quote(expected.Tags[key])
+
+		if !ok {
+			messages = append(messages, description+" missing struct tag `"+expectedTag+"`")
+		} else if value != expected.Tags[key] {
+			messages = append(messages, description+" has unexpected struct tag. Expected `"+expectedTag+"`, found `"+key+":"+strconv.Quote(value)+"`")
+		}
+	}
+
+	return messages
+}
+
+// Returns the struct's field with the same name apart from capitalization (e.g., "balance" for "Balance")
+func similarField(structType reflect.Type, name string) (reflect.StructField, bool) {
+
+	for i := 0; i < structType.NumField(); i++ {
+		if field := structType.Field(i); strings.EqualFold(field.Name, name) {
+			return field, true
+		}
+	}
+
+	return reflect.StructField{}, false
+}
+
+// Builds the error message used when a field's name only differs by capitalization
+func similarFieldMessage(structName string, expected string, actual string) string {
+
+	description := structName + " field '" + actual + "'"
+
+	switch {
+	case token.IsExported(expected) && !token.IsExported(actual):
+		return description + " must be exported (named '" + expected + "')"
+	case !token.IsExported(expected) && token.IsExported(actual):
+		return description + " must not be exported (named '" + expected + "')"
+	}
+
+	return description + " has unexpected capitalization. Expected '" + expected + "'"
+}
+
+// Shows how the field would be declared if it were embedded
+func embeddedExample(expected FieldAnatomy, field reflect.StructField) string {
+
+	if expected.Type != nil {
+		return expected.Type.String()
+	}
+
+	return field.Type.String()
+}
@@ -0,0 +1,48 @@
+package helpers
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// Amount of time a function/method is given to complete when
+// the test doesn't specify its own Timeout
+var DefaultTimeout = 3 * time.Second
+
+// Factor applied to every test timeout. Useful for running the same
+// test suite on a fast laptop and a busy grading server.
+// Initialized from the TimeoutScaleEnvVar environment variable (if set).
+var TimeoutScale = 1.0
+
+// Environment variable used to initialize TimeoutScale (e.g., HELPERS_TIMEOUT_SCALE=2.5)
+const TimeoutScaleEnvVar = "HELPERS_TIMEOUT_SCALE"
+
+func init() {
+
+	if value, ok := os.LookupEnv(TimeoutScaleEnvVar); ok {
+
+		scale, err := strconv.ParseFloat(value, 64)
+
+		if err != nil || scale <= 0 {
+			invalidSetting(TimeoutScaleEnvVar, value, "a positive number")
+		} else {
+			TimeoutScale = scale
+		}
+	}
+}
+
+// Returns the amount of time a test is given to complete.
+// A timeout <= 0 means use DefaultTimeout. The result is scaled by TimeoutScale.
+func scaledTimeout(timeout time.Duration) time.Duration {
+
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	if TimeoutScale > 0 {
+		timeout = time.Duration(float64(timeout) * TimeoutScale)
+	}
+
+	return timeout
+}
@@ -0,0 +1,623 @@
+package helpers
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// Output test cases for a function, written using plain Go values.
+// F is the signature the assignment requires. The submission's function is
+// checked against it (a function with a different signature fails the anatomy check):
+//
+//	cases := helpers.NewFuncCases[func(...float64) float64]("Average", Average)
+//	cases.Case(1, 2, 3).Returns(2)
+//	cases.Case().Returns(0).Stdout("No numbers")
+//	cases.Call(func(average func(...float64) float64) { average(4, 5) }).Returns(4.5)
+//	cases.Run(randomSeed, t)
+//
+// Untyped constants are converted to F's parameter and return types (e.g., the
+// 2 above becomes a float64), and the arguments for a variadic parameter are
+// listed individually. The arguments of cases added using Call are checked by the compiler.
+// The cases compile down to FuncOutputTest values.
+type FuncCases[F any] struct {
+	name     string
+	function interface{}
+	cases    []*TypedCase
+}
+
+// Creates an empty set of cases for the submission's function
+func NewFuncCases[F any](name string, function interface{}) *FuncCases[F] {
+
+	FuncType[F]() // F must be a function type
+
+	return &FuncCases[F]{name: name, function: function}
+}
+
+// Adds a case that calls the function with the specified arguments
+func (c *FuncCases[F]) Case(args ...interface{}) *TypedCase {
+
+	tc := &TypedCase{args: args}
+	c.cases = append(c.cases, tc)
+
+	return tc
+}
+
+// Adds a case that calls the function with the arguments record passes to its
+// parameter (which must be called exactly once)
+func (c *FuncCases[F]) Call(record func(F)) *TypedCase {
+
+	tc := &TypedCase{values: recordCall(record)}
+	c.cases = append(c.cases, tc)
+
+	return tc
+}
+
+// Builds the function output tests.
+// Returns an error if a case doesn't fit F (e.g., a value can't be converted to the type it's used as).
+func (c *FuncCases[F]) Tests() ([]FuncOutputTest, error) {
+
+	functionType := FuncType[F]()
+
+	var tests []FuncOutputTest
+
+	for i, tc := range c.cases {
+
+		args, returns, err := tc.convert(functionType)
+		if err != nil {
+			return nil, errors.New("Function '" + c.name + "' case " + strconv.Itoa(i+1) + ": " + err.Error())
+		}
+
+		tests = append(tests, FuncOutputTest{
+			Name:                c.name,
+			Obj:                 c.function,
+			Args:                args,
+			Variadic:            functionType.IsVariadic(),
+			StdinStrings:        tc.stdin,
+			IgnoreStdout:        tc.ignoreStdout,
+			StdoutStrings:       tc.stdout,
+			StdoutMatchers:      tc.stdoutMatchers,
+			IgnoreStderr:        tc.ignoreStderr,
+			StderrStrings:       tc.stderr,
+			StderrMatchers:      tc.stderrMatchers,
+			HideExpected:        tc.hideExpected,
+			IgnoreReturns:       tc.ignoreReturns,
+			Returns:             returns,
+			ReturnComparators:   tc.comparators,
+			ReturnErrorMatchers: tc.errorMatchers,
+			ReturnDetail:        tc.returnDetail,
+			Timeout:             tc.timeout,
+			Isolate:             tc.isolate,
+			ExpectExit:          tc.expectExit,
+			ExpectPanic:         tc.expectPanic,
+			PanicMatcher:        tc.panicMatcher,
+			ExitCode:            tc.exitCode,
+			Points:              tc.points,
+			Ungraded:            tc.ungraded,
+			Category:            tc.category,
+		})
+	}
+
+	return tests, nil
+}
+
+// Runs the cases using RunFunctionOutputTests
+func (c *FuncCases[F]) Run(randomSeed int64, t *testing.T) {
+
+	tests, err := c.Tests()
+	if err != nil {
+		t.Fatal("Invalid test case. " + err.Error())
+	}
+
+	RunFunctionOutputTests(tests, randomSeed, t)
+}
+
+// Output test cases for a method, written using plain Go values.
+// M is the signature the assignment requires, not including the receiver:
+//
+//	cases := helpers.NewMethodCases[func(float64) float64]("Deposit")
+//	cases.Case(25).Returns(25)
+//	cases.Run(&Account{}, randomSeed, t)
+//
+// The cases compile down to MethodOutputTest values (see FuncCases).
+type MethodCases[M any] struct {
+	name  string
+	cases []*TypedCase
+}
+
+// Creates an empty set of cases for the method
+func NewMethodCases[M any](name string) *MethodCases[M] {
+
+	FuncType[M]() // M must be a function type
+
+	return &MethodCases[M]{name: name}
+}
+
+// Adds a case that calls the method with the specified arguments (not including the receiver)
+func (c *MethodCases[M]) Case(args ...interface{}) *TypedCase {
+
+	tc := &TypedCase{args: args}
+	c.cases = append(c.cases, tc)
+
+	return tc
+}
+
+// Adds a case that calls the method with the arguments record passes to its
+// parameter (which must be called exactly once)
+func (c *MethodCases[M]) Call(record func(M)) *TypedCase {
+
+	tc := &TypedCase{values: recordCall(record)}
+	c.cases = append(c.cases, tc)
+
+	return tc
+}
+
+// Builds the method output tests.
+// Returns an error if a case doesn't fit M (e.g., a value can't be converted to the type it's used as).
+func (c *MethodCases[M]) Tests() ([]MethodOutputTest, error) {
+
+	methodType := FuncType[M]()
+
+	var tests []MethodOutputTest
+
+	for i, tc := range c.cases {
+
+		args, returns, err := tc.convert(methodType)
+		if err != nil {
+			return nil, errors.New("Method '" + c.name + "' case " + strconv.Itoa(i+1) + ": " + err.Error())
+		}
+
+		tests = append(tests, MethodOutputTest{
+			Name:                c.name,
+			Args:                args,
+			Variadic:            methodType.IsVariadic(),
+			StdinStrings:        tc.stdin,
+			IgnoreStdout:        tc.ignoreStdout,
+			StdoutStrings:       tc.stdout,
+			StdoutMatchers:      tc.stdoutMatchers,
+			IgnoreStderr:        tc.ignoreStderr,
+			StderrStrings:       tc.stderr,
+			StderrMatchers:      tc.stderrMatchers,
+			HideExpected:        tc.hideExpected,
+			IgnoreReturns:       tc.ignoreReturns,
+			Returns:             returns,
+			ReturnComparators:   tc.comparators,
+			ReturnErrorMatchers: tc.errorMatchers,
+			ReturnDetail:        tc.returnDetail,
+			Timeout:             tc.timeout,
+			Isolate:             tc.isolate,
+			ExpectExit:          tc.expectExit,
+			ExpectPanic:         tc.expectPanic,
+			PanicMatcher:        tc.panicMatcher,
+			ExitCode:            tc.exitCode,
+			Points:              tc.points,
+			Ungraded:            tc.ungraded,
+			Category:            tc.category,
+		})
+	}
+
+	return tests, nil
+}
+
+// Runs the cases using RunMethodOutputTests
+// testObject = the object being tested or a pointer to it
+func (c *MethodCases[M]) Run(testObject interface{}, randomSeed int64, t *testing.T) {
+
+	tests, err := c.Tests()
+	if err != nil {
+		t.Fatal("Invalid test case. " + err.Error())
+	}
+
+	RunMethodOutputTests(testObject, tests, randomSeed, t)
+}
+
+// Calls record with a function of type F that records the arguments it's called with.
+// Returns the recorded arguments (the arguments for a variadic parameter are gathered into a slice).
+func recordCall[F any](record func(F)) []reflect.Value {
+
+	functionType := FuncType[F]()
+
+	var calls [][]reflect.Value
+
+	recorder := reflect.MakeFunc(functionType, func(args []reflect.Value) []reflect.Value {
+
+		calls = append(calls, args)
+
+		var results []reflect.Value
+		for i := 0; i < functionType.NumOut(); i++ {
+			results = append(results, reflect.Zero(functionType.Out(i)))
+		}
+
+		return results
+	})
+
+	record(recorder.Interface().(F))
+
+	if len(calls) != 1 {
+		panic("Call requires a function that calls its parameter exactly once, found " + strconv.Itoa(len(calls)) + " call(s)")
+	}
+
+	return calls[0]
+}
+
+// A single function or method case.
+// The setters return the case so they can be chained.
+type TypedCase struct {
+	args           []interface{}
+	values         []reflect.Value // arguments recorded by Call (already typed)
+	returns        []interface{}
+	stdin          []string
+	ignoreStdout   bool
+	stdout         []string
+	stdoutMatchers []OutputMatcher
+	ignoreStderr   bool
+	stderr         []string
+	stderrMatchers []OutputMatcher
+	hideExpected   bool
+	ignoreReturns  bool
+	comparators    []Comparator
+	errorMatchers  []ErrorMatcher
+	returnDetail   Detail
+	timeout        time.Duration
+	isolate        bool
+	expectExit     bool
+	expectPanic    bool
+	panicMatcher   ErrorMatcher
+	exitCode       int
+	points         float64
+	ungraded       bool
+	category       string
+}
+
+// Sets the expected return values
+func (tc *TypedCase) Returns(values ...interface{}) *TypedCase {
+	tc.returns = values
+	return tc
+}
+
+// Sets the lines of user input
+func (tc *TypedCase) Stdin(lines ...string) *TypedCase {
+	tc.stdin = lines
+	return tc
+}
+
+// Sets the expected output lines
+func (tc *TypedCase) Stdout(lines ...string) *TypedCase {
+	tc.stdout = lines
+	return tc
+}
+
+// Sets the matchers used for the output (e.g., Regex, Contains, FloatWithin)
+func (tc *TypedCase) StdoutMatching(matchers ...OutputMatcher) *TypedCase {
+	tc.stdoutMatchers = matchers
+	return tc
+}
+
+// Doesn't check the output
+func (tc *TypedCase) IgnoreStdout() *TypedCase {
+	tc.ignoreStdout = true
+	return tc
+}
+
+// Sets the expected error output (stderr) lines (none = nothing should be written to stderr)
+func (tc *TypedCase) Stderr(lines ...string) *TypedCase {
+	tc.stderr = append([]string{}, lines...)
+	return tc
+}
+
+// Sets the matchers used for the error output (stderr)
+func (tc *TypedCase) StderrMatching(matchers ...OutputMatcher) *TypedCase {
+	tc.stderrMatchers = matchers
+	return tc
+}
+
+// Doesn't check the error output (stderr), even if CheckStderr is true
+func (tc *TypedCase) IgnoreStderr() *TypedCase {
+	tc.ignoreStderr = true
+	return tc
+}
+
+// Doesn't check the return values
+func (tc *TypedCase) IgnoreReturns() *TypedCase {
+	tc.ignoreReturns = true
+	return tc
+}
+
+// Sets the comparator used for each return position (nil entries use reflect.DeepEqual)
+func (tc *TypedCase) Compare(comparators ...Comparator) *TypedCase {
+	tc.comparators = comparators
+	return tc
+}
+
+// Sets the error matcher used for each return position holding an error
+// (e.g., ErrorIs(ErrNotFound); nil entries aren't used)
+func (tc *TypedCase) ReturnErrors(matchers ...ErrorMatcher) *TypedCase {
+	tc.errorMatchers = matchers
+	return tc
+}
+
+// Sets the amount of detail shown when a return value doesn't match (see Detail)
+func (tc *TypedCase) ReturnDetail(detail Detail) *TypedCase {
+	tc.returnDetail = detail
+	return tc
+}
+
+// Leaves the expected output out of failure messages (e.g., hidden tests)
+func (tc *TypedCase) Hidden() *TypedCase {
+	tc.hideExpected = true
+	return tc
+}
+
+// Sets the amount of time the call is given to complete
+func (tc *TypedCase) Timeout(timeout time.Duration) *TypedCase {
+	tc.timeout = timeout
+	return tc
+}
+
+// Runs the call in a separate process (see IsolateTests)
+func (tc *TypedCase) Isolate() *TypedCase {
+	tc.isolate = true
+	return tc
+}
+
+// Expects the call to panic with a value the matcher accepts (nil = any value)
+func (tc *TypedCase) Panics(matcher ErrorMatcher) *TypedCase {
+	tc.expectPanic = true
+	tc.panicMatcher = matcher
+	return tc
+}
+
+// Expects the call to end the program with the exit status (e.g., os.Exit or log.Fatal)
+func (tc *TypedCase) Exits(code int) *TypedCase {
+	tc.expectExit = true
+	tc.exitCode = code
+	return tc
+}
+
+// Sets the weight and category used for scoring
+func (tc *TypedCase) Score(points float64, category string) *TypedCase {
+	tc.points = points
+	tc.category = category
+	return tc
+}
+
+// Reports the case without it being worth any points
+func (tc *TypedCase) Ungraded() *TypedCase {
+	tc.ungraded = true
+	return tc
+}
+
+// Converts the case's arguments and returns to the signature's parameter and
+// return types. Arguments for a variadic parameter are gathered into a slice.
+func (tc *TypedCase) convert(functionType reflect.Type) ([]reflect.Value, []reflect.Value, error) {
+
+	args := tc.values
+
+	if args == nil {
+
+		var err error
+		if args, err = tc.convertArgs(functionType); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var returns []reflect.Value
+
+	if (tc.ignoreReturns || tc.expectPanic) && len(tc.returns) == 0 {
+
+		// Returns are still needed for the anatomy check
+		for i := 0; i < functionType.NumOut(); i++ {
+			returns = append(returns, reflect.Zero(functionType.Out(i)))
+		}
+
+		return args, returns, nil
+	}
+
+	if len(tc.returns) != functionType.NumOut() {
+		return nil, nil, errors.New(strconv.Itoa(len(tc.returns)) + " return value(s) given, expected " + strconv.Itoa(functionType.NumOut()))
+	}
+
+	for i, ret := range tc.returns {
+
+		value, err := convertTyped(ret, functionType.Out(i))
+		if err != nil {
+			return nil, nil, errors.New("return value " + strconv.Itoa(i+1) + ": " + err.Error())
+		}
+
+		returns = append(returns, value)
+	}
+
+	return args, returns, nil
+}
+
+// Converts the case's arguments to the signature's parameter types
+func (tc *TypedCase) convertArgs(functionType reflect.Type) ([]reflect.Value, error) {
+
+	var params []reflect.Type
+	for i := 0; i < functionType.NumIn(); i++ {
+		params = append(params, functionType.In(i))
+	}
+
+	var args []reflect.Value
+
+	fixed := len(params)
+	if functionType.IsVariadic() {
+		fixed--
+	}
+
+	if len(tc.args) < fixed || (!functionType.IsVariadic() && len(tc.args) != fixed) {
+		return nil, errors.New(strconv.Itoa(len(tc.args)) + " argument(s) given, expected " + strconv.Itoa(fixed))
+	}
+
+	for i := 0; i < fixed; i++ {
+
+		value, err := convertTyped(tc.args[i], params[i])
+		if err != nil {
+			return nil, errors.New("argument " + strconv.Itoa(i+1) + ": " + err.Error())
+		}
+
+		args = append(args, value)
+	}
+
+	if functionType.IsVariadic() {
+
+		sliceType := params[fixed]
+		variadic := reflect.MakeSlice(sliceType, 0, len(tc.args)-fixed)
+
+		for i := fixed; i < len(tc.args); i++ {
+
+			value, err := convertTyped(tc.args[i], sliceType.Elem())
+			if err != nil {
+				return nil, errors.New("argument " + strconv.Itoa(i+1) + ": " + err.Error())
+			}
+
+			variadic = reflect.Append(variadic, value)
+		}
+
+		args = append(args, variadic)
+	}
+
+	return args, nil
+}
+
+// Converts the value to the specified type.
+// Values that can be assigned to the type are used as is. Values with the default
+// type of an untyped constant (int, float64, rune, string, bool and complex128)
+// are converted if the constant could have been used as the type (e.g., 3 as a float64,
+// 2.0 as an int or "x" as a named string type). Anything else is an error.
+func convertTyped(v interface{}, t reflect.Type) (reflect.Value, error) {
+
+	if v == nil {
+
+		switch t.Kind() {
+		case reflect.Pointer, reflect.Interface, reflect.Slice, reflect.Map, reflect.Func, reflect.Chan:
+			return reflect.Zero(t), nil
+		}
+
+		return reflect.Value{}, errors.New("cannot use nil as " + t.String())
+	}
+
+	value := reflect.ValueOf(v)
+
+	if value.Type().AssignableTo(t) {
+		return value.Convert(t), nil
+	}
+
+	result := reflect.New(t).Elem()
+	converted := false
+
+	switch v := v.(type) {
+
+	case int:
+		converted = setInteger(result, int64(v))
+
+	case rune:
+		converted = setInteger(result, int64(v))
+
+	case float64:
+		converted = setConstant(result, v, complex(v, 0))
+
+	case complex128:
+		converted = setConstant(result, real(v), v)
+
+	case string:
+		if t.Kind() == reflect.String {
+			result.SetString(v)
+			converted = true
+		}
+
+	case bool:
+		if t.Kind() == reflect.Bool {
+			result.SetBool(v)
+			converted = true
+		}
+	}
+
+	if !converted {
+		return reflect.Value{}, errors.New("cannot use " + formatValue(value) + " (" + value.Type().String() + ") as " + t.String())
+	}
+
+	return result, nil
+}
+
+// Sets the integer constant if it's representable by the value's type.
+// Integer types are set exactly (going through float64 would round large values).
+// Returns false if it isn't representable (e.g., -1 as a uint or 300 as an int8).
+func setInteger(value reflect.Value, n int64) bool {
+
+	switch value.Kind() {
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+
+		if value.OverflowInt(n) {
+			return false
+		}
+
+		value.SetInt(n)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+
+		if n < 0 || value.OverflowUint(uint64(n)) {
+			return false
+		}
+
+		value.SetUint(uint64(n))
+
+	default:
+		return setConstant(value, float64(n), complex(float64(n), 0))
+	}
+
+	return true
+}
+
+// Sets the numeric value if the constant is representable by its type.
+// Returns false if it isn't (e.g., 2.5 as an int or -1 as a uint).
+func setConstant(value reflect.Value, f float64, c complex128) bool {
+
+	if imag(c) != 0 && value.Kind() != reflect.Complex64 && value.Kind() != reflect.Complex128 {
+		return false
+	}
+
+	switch value.Kind() {
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+
+		if f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 || value.OverflowInt(int64(f)) {
+			return false
+		}
+
+		value.SetInt(int64(f))
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+
+		if f != math.Trunc(f) || f < 0 || f >= math.MaxUint64 || value.OverflowUint(uint64(f)) {
+			return false
+		}
+
+		value.SetUint(uint64(f))
+
+	case reflect.Float32, reflect.Float64:
+
+		if value.OverflowFloat(f) {
+			return false
+		}
+
+		value.SetFloat(f)
+
+	case reflect.Complex64, reflect.Complex128:
+
+		if value.OverflowComplex(c) {
+			return false
+		}
+
+		value.SetComplex(c)
+
+	default:
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,241 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type typedName string
+
+type typedAccount struct {
+	balance float64
+}
+
+func (a *typedAccount) Deposit(amount float64) float64 {
+	a.balance += amount
+	return a.balance
+}
+
+func TestConvertTyped(t *testing.T) {
+
+	tests := []struct {
+		value    interface{}
+		t        reflect.Type
+		expected interface{}
+		ok       bool
+	}{
+		{3, reflect.TypeOf(0.0), 3.0, true},
+		{2.0, reflect.TypeOf(0), 2, true},
+		{2.5, reflect.TypeOf(0), nil, false},
+		{-1, reflect.TypeOf(uint(0)), nil, false},
+		{300, reflect.TypeOf(int8(0)), nil, false},
+		{math.MaxInt64, reflect.TypeOf(int64(0)), int64(math.MaxInt64), true},
+		{math.MinInt64, reflect.TypeOf(int64(0)), int64(math.MinInt64), true},
+		{9007199254740993, reflect.TypeOf(int64(0)), int64(9007199254740993), true},
+		{math.MaxInt64, reflect.TypeOf(uint64(0)), uint64(math.MaxInt64), true},
+		{9007199254740993, reflect.TypeOf(uint(0)), uint(9007199254740993), true},
+		{math.MaxInt64, reflect.TypeOf(int32(0)), nil, false},
+		{-129, reflect.TypeOf(int8(0)), nil, false},
+		{-128, reflect.TypeOf(int8(0)), int8(-128), true},
+		{256, reflect.TypeOf(byte(0)), nil, false},
+		{'世', reflect.TypeOf(int64(0)), int64('世'), true},
+		{'世', reflect.TypeOf(byte(0)), nil, false},
+		{3, reflect.TypeOf(complex64(0)), complex64(3), true},
+		{'a', reflect.TypeOf(byte(0)), byte('a'), true},
+		{"x", reflect.TypeOf(typedName("")), typedName("x"), true},
+		{"x", reflect.TypeOf(0), nil, false},
+		{true, reflect.TypeOf(false), true, true},
+		{nil, reflect.TypeOf([]int{}), []int(nil), true},
+		{nil, reflect.TypeOf(0), nil, false},
+		{[]int{1, 2}, reflect.TypeOf([]int{}), []int{1, 2}, true},
+		{[]int{1, 2}, reflect.TypeOf([]float64{}), nil, false},
+	}
+
+	for _, test := range tests {
+
+		value, err := convertTyped(test.value, test.t)
+
+		if !test.ok {
+			if err == nil {
+				t.Errorf("convertTyped(%#v, %s): expected an error, got %#v", test.value, test.t, value.Interface())
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("convertTyped(%#v, %s): unexpected error %v", test.value, test.t, err)
+			continue
+		}
+
+		if value.Type() != test.t || !reflect.DeepEqual(value.Interface(), test.expected) {
+			t.Errorf("convertTyped(%#v, %s) = %#v, expected %#v", test.value, test.t, value.Interface(), test.expected)
+		}
+	}
+}
+
+func TestFuncCasesCheckSubmissionAgainstF(t *testing.T) {
+
+	// averageReturningInt and sumWithExtraParameter are declared in spec_test.go
+	average := NewFuncCases[func(...float64) float64]("Average", averageReturningInt)
+	average.Case(1, 2, 3).Returns(2)
+
+	sum := NewFuncCases[func([]int) int]("Sum", sumWithExtraParameter)
+	sum.Case([]int{1, 2}).Returns(3)
+
+	for _, test := range []struct {
+		tests    func() ([]FuncOutputTest, error)
+		expected string
+	}{
+		{average.Tests, "returned unexpected data type. Expected type float64, received type int"},
+		{sum.Tests, "has unexpected number of parameters"},
+	} {
+
+		tests, err := test.tests()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		messages := checkFunctionAnatomy(convertFuncOutputTestToAnatomyTest(tests[0]))
+		if len(messages) == 0 || !strings.Contains(messages[0], test.expected) {
+			t.Errorf("expected anatomy failure containing %q, got %v", test.expected, messages)
+		}
+	}
+}
+
+func TestFuncCasesTests(t *testing.T) {
+
+	cases := NewFuncCases[func(string, ...float64) float64]("Average", nil)
+	cases.Case("label", 1, 2).Returns(1.5)
+	cases.Call(func(average func(string, ...float64) float64) { average("label", 4, 5) }).Returns(4.5)
+
+	tests, err := cases.Tests()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedArgs := [][]interface{}{
+		{"label", []float64{1, 2}},
+		{"label", []float64{4, 5}},
+	}
+
+	for i, test := range tests {
+
+		if !test.Variadic {
+			t.Errorf("case %d: expected a variadic test", i+1)
+		}
+
+		if !valuesEqual(test.Args, expectedArgs[i]) {
+			t.Errorf("case %d: args = %v, expected %v", i+1, test.Args, expectedArgs[i])
+		}
+	}
+
+	if !valuesEqual(tests[1].Returns, []interface{}{4.5}) {
+		t.Errorf("returns = %v, expected [4.5]", tests[1].Returns)
+	}
+
+	// Cases that don't fit F are the test author's mistake
+	bad := NewFuncCases[func(int) int]("Double", nil)
+	bad.Case(1, 2).Returns(2)
+
+	if _, err := bad.Tests(); err == nil || !strings.Contains(err.Error(), "2 argument(s) given, expected 1") {
+		t.Errorf("expected an argument count error, got %v", err)
+	}
+}
+
+var errTypedMissing = errors.New("missing")
+
+// Returns the length of the key, or a wrapped errTypedMissing for an empty key
+func typedLookup(key string) (int, error) {
+
+	if key == "" {
+		return 0, fmt.Errorf("lookup: %w", errTypedMissing)
+	}
+
+	return len(key), nil
+}
+
+func TestTypedCaseScoringAndErrors(t *testing.T) {
+
+	cases := NewFuncCases[func(string) (int, error)]("typedLookup", typedLookup)
+	cases.Case("").Returns(0, nil).ReturnErrors(nil, ErrorIs(errTypedMissing)).ReturnDetail(DetailSummary).Ungraded()
+	cases.Case("abc").Returns(3, nil).Score(2, "Lookup")
+
+	tests, err := cases.Tests()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !tests[0].Ungraded || tests[0].ReturnDetail != DetailSummary || len(tests[0].ReturnErrorMatchers) != 2 {
+		t.Errorf("case 1: setters weren't passed through: %+v", tests[0])
+	}
+
+	if tests[1].Ungraded || tests[1].Points != 2 || tests[1].Category != "Lookup" {
+		t.Errorf("case 2: setters weren't passed through: %+v", tests[1])
+	}
+
+	for i, test := range tests {
+		if messages := checkFunctionOutput(test, 0, t); len(messages) > 0 {
+			t.Errorf("case %d: unexpected failure %v", i+1, messages)
+		}
+	}
+
+	methods := NewMethodCases[func(float64) float64]("Deposit")
+	methods.Case(25).Returns(25).Ungraded().ReturnDetail(DetailSummary).ReturnErrors(nil)
+
+	methodTests, err := methods.Tests()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !methodTests[0].Ungraded || methodTests[0].ReturnDetail != DetailSummary || len(methodTests[0].ReturnErrorMatchers) != 1 {
+		t.Errorf("method case: setters weren't passed through: %+v", methodTests[0])
+	}
+}
+
+func TestMethodCases(t *testing.T) {
+
+	cases := NewMethodCases[func(float64) float64]("Deposit")
+	cases.Case(25).Returns(25)
+	cases.Call(func(deposit func(float64) float64) { deposit(5) }).Returns(30)
+
+	tests, err := cases.Tests()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	account := &typedAccount{}
+
+	for _, test := range tests {
+		if messages := checkMethodOutput(account, test, 0, t); len(messages) > 0 {
+			t.Errorf("unexpected failure %v", messages)
+		}
+	}
+
+	// A method with a different signature fails the anatomy check
+	wrong := NewMethodCases[func(int) int]("Deposit")
+	wrong.Case(25).Returns(25)
+
+	tests, err = wrong.Tests()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if messages := checkMethodAnatomy(account, convertMethodOutputTestToAnatomyTest(tests[0])); len(messages) == 0 {
+		t.Error("expected an anatomy failure for a method with a different signature")
+	}
+}
+
+func TestCallRequiresOneCall(t *testing.T) {
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic when the recorder isn't called")
+		}
+	}()
+
+	NewFuncCases[func(int) int]("Double", nil).Call(func(func(int) int) {})
+}
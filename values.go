@@ -0,0 +1,291 @@
+package helpers
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Amount of detail included in return value failure messages
+type Detail int
+
+// Detail enum values
+const (
+	DetailDefault Detail = iota // use DefaultReturnDetail (DetailSummary for tests with HideExpected set)
+	DetailSummary               // generic message that doesn't reveal any values
+	DetailValues                // arguments, mismatched position, expected/actual values and their differences
+)
+
+// Amount of detail used for tests that don't specify their own ReturnDetail
+var DefaultReturnDetail = DetailValues
+
+// Maximum number of slice/map elements and struct differences shown in failure messages
+const maxShownElements = 20
+
+// Formats the value the way it would be written in Go code
+// (e.g., "hello" is quoted, structs show field names, maps are sorted by key)
+func formatValue(v reflect.Value) string {
+
+	var sb strings.Builder
+	writeValue(&sb, v, 0)
+	return sb.String()
+}
+
+// Writes the formatted value. depth guards against cyclic data structures.
+func writeValue(sb *strings.Builder, v reflect.Value, depth int) {
+
+	if !v.IsValid() {
+		sb.WriteString("nil")
+		return
+	}
+
+	if depth > 10 {
+		sb.WriteString("...")
+		return
+	}
+
+	switch v.Kind() {
+
+	case reflect.String:
+		sb.WriteString(strconv.Quote(v.String()))
+
+	case reflect.Float32, reflect.Float64:
+		sb.WriteString(strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()))
+
+	case reflect.Interface:
+		if v.IsNil() {
+			sb.WriteString("nil")
+		} else {
+			writeValue(sb, v.Elem(), depth+1)
+		}
+
+	case reflect.Pointer:
+		if v.IsNil() {
+			sb.WriteString("nil")
+		} else {
+			sb.WriteString("&")
+			writeValue(sb, v.Elem(), depth+1)
+		}
+
+	case reflect.Slice, reflect.Array:
+
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			sb.WriteString(v.Type().String() + "(nil)")
+			return
+		}
+
+		sb.WriteString(v.Type().String() + "{")
+
+		for i := 0; i < v.Len(); i++ {
+
+			if i == maxShownElements {
+				sb.WriteString(", ... (" + strconv.Itoa(v.Len()) + " elements)")
+				break
+			}
+
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+
+			writeValue(sb, v.Index(i), depth+1)
+		}
+
+		sb.WriteString("}")
+
+	case reflect.Map:
+
+		if v.IsNil() {
+			sb.WriteString(v.Type().String() + "(nil)")
+			return
+		}
+
+		sb.WriteString(v.Type().String() + "{")
+
+		for i, key := range sortedMapKeys(v) {
+
+			if i == maxShownElements {
+				sb.WriteString(", ... (" + strconv.Itoa(v.Len()) + " elements)")
+				break
+			}
+
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+
+			writeValue(sb, key, depth+1)
+			sb.WriteString(": ")
+			writeValue(sb, v.MapIndex(key), depth+1)
+		}
+
+		sb.WriteString("}")
+
+	case reflect.Struct:
+
+		sb.WriteString(v.Type().String() + "{")
+
+		for i := 0; i < v.NumField(); i++ {
+
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+
+			sb.WriteString(v.Type().Field(i).Name + ": ")
+			writeValue(sb, v.Field(i), depth+1)
+		}
+
+		sb.WriteString("}")
+
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		if v.IsNil() {
+			sb.WriteString(v.Type().String() + "(nil)")
+		} else {
+			sb.WriteString(v.Type().String())
+		}
+
+	default:
+		// Unexported struct fields can't be converted back to interface{},
+		// but fmt can still print the basic kinds using reflection
+		sb.WriteString(fmt.Sprint(v))
+	}
+}
+
+// Returns the map's keys in a predictable order
+func sortedMapKeys(v reflect.Value) []reflect.Value {
+
+	keys := v.MapKeys()
+
+	sort.Slice(keys, func(i int, j int) bool {
+		return formatValue(keys[i]) < formatValue(keys[j])
+	})
+
+	return keys
+}
+
+// Describes the differences between the expected and actual values
+// (e.g., ".Total: expected 5, actual 6" or "[2]: expected "a", actual "b"").
+// Returns nil if no specific differences could be found.
+func valueDifferences(expected reflect.Value, actual reflect.Value) []string {
+
+	var differences []string
+	collectDifferences(&differences, "", expected, actual, 0)
+
+	if len(differences) > maxShownElements {
+		differences = append(differences[:maxShownElements], "... ("+strconv.Itoa(len(differences))+" differences)")
+	}
+
+	return differences
+}
+
+// Adds a description of every difference between the values to the list
+func collectDifferences(differences *[]string, path string, expected reflect.Value, actual reflect.Value, depth int) {
+
+	if len(*differences) > maxShownElements || depth > 10 {
+		return
+	}
+
+	difference := func(description string) {
+		name := path
+		if name == "" {
+			name = "value"
+		}
+		*differences = append(*differences, name+": "+description)
+	}
+
+	if !expected.IsValid() || !actual.IsValid() {
+		if expected.IsValid() != actual.IsValid() {
+			difference("expected " + formatValue(expected) + ", actual " + formatValue(actual))
+		}
+		return
+	}
+
+	if expected.Type() != actual.Type() {
+		difference("expected type " + expected.Type().String() + ", actual type " + actual.Type().String())
+		return
+	}
+
+	switch expected.Kind() {
+
+	case reflect.Pointer, reflect.Interface:
+
+		if expected.IsNil() || actual.IsNil() {
+			if expected.IsNil() != actual.IsNil() {
+				difference("expected " + formatValue(expected) + ", actual " + formatValue(actual))
+			}
+			return
+		}
+
+		collectDifferences(differences, path, expected.Elem(), actual.Elem(), depth+1)
+
+	case reflect.Struct:
+
+		for i := 0; i < expected.NumField(); i++ {
+			collectDifferences(differences, path+"."+expected.Type().Field(i).Name, expected.Field(i), actual.Field(i), depth+1)
+		}
+
+	case reflect.Slice, reflect.Array:
+
+		if expected.Kind() == reflect.Slice && expected.IsNil() != actual.IsNil() {
+			difference("expected " + formatValue(expected) + ", actual " + formatValue(actual))
+			return
+		}
+
+		if expected.Len() != actual.Len() {
+			difference("expected length " + strconv.Itoa(expected.Len()) + ", actual length " + strconv.Itoa(actual.Len()))
+		}
+
+		for i := 0; i < expected.Len() && i < actual.Len(); i++ {
+			collectDifferences(differences, path+"["+strconv.Itoa(i)+"]", expected.Index(i), actual.Index(i), depth+1)
+		}
+
+	case reflect.Map:
+
+		if expected.IsNil() != actual.IsNil() {
+			difference("expected " + formatValue(expected) + ", actual " + formatValue(actual))
+			return
+		}
+
+		for _, key := range sortedMapKeys(expected) {
+
+			keyPath := path + "[" + formatValue(key) + "]"
+
+			if actualValue := actual.MapIndex(key); actualValue.IsValid() {
+				collectDifferences(differences, keyPath, expected.MapIndex(key), actualValue, depth+1)
+			} else {
+				*differences = append(*differences, keyPath+": missing")
+			}
+		}
+
+		for _, key := range sortedMapKeys(actual) {
+			if !expected.MapIndex(key).IsValid() {
+				*differences = append(*differences, path+"["+formatValue(key)+"]: unexpected")
+			}
+		}
+
+	default:
+
+		if formatValue(expected) != formatValue(actual) {
+			difference("expected " + formatValue(expected) + ", actual " + formatValue(actual))
+		}
+	}
+}
+
+// Formats the argument list for a call (e.g., "(1, "a", []int{2, 3}...)")
+func formatArguments(args []reflect.Value, variadic bool) string {
+
+	var formatted []string
+
+	for i, arg := range args {
+
+		text := formatValue(arg)
+
+		if variadic && i == len(args)-1 {
+			text += "..."
+		}
+
+		formatted = append(formatted, text)
+	}
+
+	return "(" + strings.Join(formatted, ", ") + ")"
+}